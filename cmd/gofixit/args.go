@@ -1,10 +1,13 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/LouisBrunner/gofixit/src/contracts"
 	"github.com/LouisBrunner/gofixit/src/utils"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
@@ -21,6 +24,28 @@ type args struct {
 	recursive           bool
 	files               []string
 	filesExcludePattern []string
+	rev                 string
+	scanArchives        bool
+	keepGoing           bool
+	followSymlinks      bool
+	format              contracts.Format
+	template            string
+	warningPeriod       time.Duration
+	html                string
+	filters             inventoryFilters
+	sortBy              string
+	addExpiry           string
+	dryRun              bool
+	maxSnoozes          uint
+	snoozeBy            string
+	snoozeReason        string
+	snoozeLog           string
+	baseline            string
+	reportUnused        bool
+	implicitExpiry      time.Duration
+	blame               bool
+	debounce            time.Duration
+	targets             []string
 	loggingLevel        logrus.Level
 }
 
@@ -54,18 +79,43 @@ func addDefault[V any](name []string, value V, typ func(name string, value V, he
 	viper.BindPFlag(configName, pflag.Lookup(flagName))
 }
 
-func getArgs() (*args, error) {
+func usage(cmd *command) func() {
+	return func() {
+		fmt.Fprintf(os.Stderr, "Usage: gofixit [command] [flags]\n\nCommands:\n")
+		for _, other := range commands {
+			fmt.Fprintf(os.Stderr, "  %-10s %s\n", other.name, other.description)
+		}
+		fmt.Fprintf(os.Stderr, "\nFlags for %s:\n%s", cmd.name, pflag.CommandLine.FlagUsages())
+	}
+}
+
+func getArgs(cmd *command, arguments []string) (*args, error) {
 	// Default values & flags
 	addDefault([]string{"Comment", "Prefixes"}, []string{"//", "#", "/*"}, pflag.StringSlice, "strings which define what a comment definition looks like")
 	addDefault([]string{"Prefixes"}, []string{"TODO", "FIXME"}, pflag.StringSlice, "strings which define what a TODO looks like")
-	addDefault([]string{"Expiry", "Pattern"}, "{{.Prefix}}(?:\\[{{.Date}}\\])?", pflag.String, "Go template used to generate a regex to match the prefix and expiry date together, careful of escaping any regex character in here")
+	addDefault([]string{"Expiry", "Pattern"}, "{{.Prefix}}(?:\\({{.Owner}}\\))?(?:\\[{{.Date}}{{.Meta}}\\])?", pflag.String, "Go template used to generate a regex to match the prefix and expiry date together, careful of escaping any regex character in here")
 	addDefault([]string{"Files"}, []string{"."}, pflag.StringSlice, "list of files to parse")
 	addDefault([]string{"Files", "Exclude", "Patterns"}, []string{""}, pflag.StringSlice, "list of patterns used to exclude files or directories")
+	addDefault([]string{"Rev"}, "", pflag.String, "git revision to read files from instead of the working tree")
+	addDefault([]string{"Scan", "Archives"}, false, pflag.Bool, "process the content of zip, jar, tar and tar.gz files")
+	addDefault([]string{"Keep", "Going"}, false, pflag.Bool, "report files which cannot be read instead of stopping at the first one")
+	addDefault([]string{"Follow", "Symlinks"}, true, pflag.Bool, "follow symlinks found while processing directories recursively (loops are skipped)")
 	addDefault([]string{"No", "Recursive"}, false, pflag.Bool, "disable processing directories recursively")
 	addDefault([]string{"Strict"}, false, pflag.Bool, "will force all matched comments to have an expiry date")
 	addDefault([]string{"Date", "Layout"}, "2006-01-02", pflag.String, "date layout format, as specified by Golang's date parsing")
+	addDefault([]string{"Format"}, "", pflag.String, "output format, one of text, pretty, json, ndjson, sarif, junit, checkstyle, github, gitlab, markdown, csv, ical, table or template (check defaults to github inside GitHub Actions, pretty in a terminal, text otherwise, list defaults to table)")
+	addDefault([]string{"Template"}, "", pflag.String, "Go template used to print each result with the template format, e.g. '{{.Path}}:{{.Line}}:{{.Column}}: {{.Message}}'")
+	addDefault([]string{"Template", "File"}, "", pflag.String, "file containing the Go template used with the template format")
 	addDefault([]string{"Logging", "Level"}, "fatal", pflag.String, "logrus log level for internal debugging")
 	addDefault([]string{"Case", "Sensitive"}, true, pflag.Bool, "should prefixes be matched as case sensitive or not")
+	addDefault([]string{"Max", "Snoozes"}, uint(0), pflag.Uint, "fail comments snoozed more than this many times (0 means no limit)")
+	addDefault([]string{"Implicit", "Expiry"}, "", pflag.String, "give undated comments an expiry date this long after their line was committed according to git blame, e.g. 90d (replaces Strict for undated comments)")
+	addDefault([]string{"Blame"}, false, pflag.Bool, "add the author, email, commit and date of the last change of each comment from git blame, the author is used as owner when none is written")
+	addDefault([]string{"Report", "Unused", "Suppressions"}, false, pflag.Bool, "report gofixit suppression directives which don't suppress any violation")
+	addDefault([]string{"Warning", "Period"}, "14d", pflag.String, "how long before their expiry comments are shown as warnings, e.g. 14d or 2w")
+	if cmd.flags != nil {
+		cmd.flags()
+	}
 
 	// Env
 	viper.SetEnvPrefix("GOFIXIT")
@@ -86,7 +136,11 @@ func getArgs() (*args, error) {
 	}
 
 	// Flags
-	pflag.Parse()
+	pflag.Usage = usage(cmd)
+	err = pflag.CommandLine.Parse(arguments)
+	if err != nil {
+		return nil, err
+	}
 
 	// Parsing
 	logLevel, err := logrus.ParseLevel(viper.GetString("LoggingLevel"))
@@ -94,6 +148,49 @@ func getArgs() (*args, error) {
 		return nil, err
 	}
 
+	tmpl := viper.GetString("Template")
+	if templateFile := viper.GetString("TemplateFile"); templateFile != "" {
+		content, err := os.ReadFile(templateFile)
+		if err != nil {
+			return nil, err
+		}
+		tmpl = strings.TrimSuffix(string(content), "\n")
+	}
+
+	warningPeriod, err := utils.ParseDuration(viper.GetString("WarningPeriod"))
+	if err != nil {
+		return nil, err
+	}
+
+	implicitExpiry := time.Duration(0)
+	if value := viper.GetString("ImplicitExpiry"); value != "" {
+		implicitExpiry, err = utils.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	debounce := time.Duration(0)
+	if value := viper.GetString("Debounce"); value != "" {
+		debounce, err = utils.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	expiringWithin := time.Duration(0)
+	if value := viper.GetString("ExpiringWithin"); value != "" {
+		expiringWithin, err = utils.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	format := contracts.Format(viper.GetString("Format"))
+	if format == "" && cmd.defaultFormat != nil {
+		format = cmd.defaultFormat()
+	}
+
 	return &args{
 		commentPrefixes:     viper.GetStringSlice("CommentPrefixes"),
 		prefixes:            viper.GetStringSlice("Prefixes"),
@@ -104,6 +201,34 @@ func getArgs() (*args, error) {
 		recursive:           !viper.GetBool("NoRecursive"),
 		files:               viper.GetStringSlice("Files"),
 		filesExcludePattern: viper.GetStringSlice("FilesExcludePatterns"),
-		loggingLevel:        logLevel,
+		rev:                 viper.GetString("Rev"),
+		scanArchives:        viper.GetBool("ScanArchives"),
+		keepGoing:           viper.GetBool("KeepGoing"),
+		followSymlinks:      viper.GetBool("FollowSymlinks"),
+		format:              format,
+		template:            tmpl,
+		warningPeriod:       warningPeriod,
+		html:                viper.GetString("Html"),
+		filters: inventoryFilters{
+			prefixes:       viper.GetStringSlice("Prefix"),
+			owners:         viper.GetStringSlice("Owner"),
+			expired:        viper.GetBool("Expired"),
+			undated:        viper.GetBool("Undated"),
+			expiringWithin: expiringWithin,
+		},
+		sortBy:         viper.GetString("Sort"),
+		addExpiry:      viper.GetString("AddExpiry"),
+		dryRun:         viper.GetBool("DryRun"),
+		maxSnoozes:     viper.GetUint("MaxSnoozes"),
+		snoozeBy:       viper.GetString("By"),
+		snoozeReason:   viper.GetString("Reason"),
+		snoozeLog:      viper.GetString("SnoozeLog"),
+		baseline:       viper.GetString("Baseline"),
+		reportUnused:   viper.GetBool("ReportUnusedSuppressions"),
+		implicitExpiry: implicitExpiry,
+		blame:          viper.GetBool("Blame"),
+		debounce:       debounce,
+		targets:        pflag.CommandLine.Args(),
+		loggingLevel:   logLevel,
 	}, nil
 }
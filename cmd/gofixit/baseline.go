@@ -0,0 +1,198 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	gofixit "github.com/LouisBrunner/gofixit/src"
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+)
+
+const defaultBaseline = ".gofixit.baseline.json"
+
+func baselineFlags() {
+	addDefault([]string{"Baseline"}, defaultBaseline, pflag.String, "file listing known issues which check ignores (created with baseline create), relative to the root of the repository")
+}
+
+// baselinePath resolves the baseline file against the root of the repository, so it is found from any directory
+func baselinePath(params *args, root string) string {
+	if filepath.IsAbs(params.baseline) {
+		return params.baseline
+	}
+	return filepath.Join(root, params.baseline)
+}
+
+// readBaseline returns nil when the file doesn't exist
+func readBaseline(filename string) (*contracts.BaselineFile, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	file := contracts.BaselineFile{}
+	err = json.Unmarshal(content, &file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid baseline %s (%w)", filename, err)
+	}
+	if file.Version != contracts.BaselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s", file.Version, filename)
+	}
+	for i, entry := range file.Entries {
+		err = validateEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid baseline %s, entry %d %w", filename, i+1, err)
+		}
+	}
+	return &file, nil
+}
+
+// validateEntry catches entries which were edited by hand, as they could never match anything
+func validateEntry(entry contracts.BaselineEntry) error {
+	if entry.Path == "" {
+		return errors.New("is missing its path")
+	}
+	if entry.Prefix == "" {
+		return errors.New("is missing its prefix")
+	}
+	if entry.Rule == "" {
+		return errors.New("is missing its rule, recreate the baseline with `gofixit baseline create`")
+	}
+	hash, err := hex.DecodeString(entry.Hash)
+	if err != nil || len(hash) != sha256.Size {
+		return fmt.Errorf("has an invalid hash %q", entry.Hash)
+	}
+	return nil
+}
+
+func writeBaseline(filename string, entries []contracts.BaselineEntry) error {
+	content, err := json.MarshalIndent(contracts.BaselineFile{
+		Version: contracts.BaselineVersion,
+		Entries: entries,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(content, '\n'), 0o644)
+}
+
+// loadBaseline returns nil when no baseline is configured or the default file doesn't exist, entries are relative
+// to root
+func loadBaseline(log *logrus.Logger, params *args, root string) (contracts.Baseline, error) {
+	if params.baseline == "" {
+		return nil, nil
+	}
+	filename := baselinePath(params, root)
+	file, err := readBaseline(filename)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		if params.baseline != defaultBaseline {
+			return nil, fmt.Errorf("no baseline found at %s", filename)
+		}
+		return nil, nil
+	}
+	baseline, err := gofixit.NewBaseline(log, contracts.BaselineConfig{
+		Entries: file.Entries,
+		Root:    root,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed while creating baseline (%w)", err)
+	}
+	return baseline, nil
+}
+
+// ignoreBaselined removes the known issues from the report, only the entries of its files are considered
+func ignoreBaselined(baseline contracts.Baseline, report *contracts.Report) contracts.BaselineMatch {
+	match := baseline.Match(report.Results, report.Files)
+	report.Results = match.New
+	report.Summary.Violations -= len(match.Baselined)
+	return match
+}
+
+// applyBaseline removes the known issues from the report and warns about the baseline entries which can be pruned
+func applyBaseline(log *logrus.Logger, params *args, root string, report *contracts.Report) error {
+	baseline, err := loadBaseline(log, params, root)
+	if err != nil || baseline == nil {
+		return err
+	}
+
+	match := ignoreBaselined(baseline, report)
+	if len(match.Baselined) > 0 {
+		fmt.Fprintf(os.Stderr, "gofixit: %d issue(s) ignored because of %s\n", len(match.Baselined), baselinePath(params, root))
+	}
+	if len(match.Fixed) > 0 {
+		fmt.Fprintf(os.Stderr, "gofixit: %d baseline entry(ies) fixed, remove them with `gofixit baseline prune`:\n", len(match.Fixed))
+		for _, entry := range match.Fixed {
+			fmt.Fprintf(os.Stderr, "  %s %s %s %s\n", entry.Path, entry.Prefix, entry.Rule, entry.Hash[:12])
+		}
+	}
+	return nil
+}
+
+func runBaseline(log *logrus.Logger, params *args) (int, error) {
+	if len(params.targets) != 1 || (params.targets[0] != "create" && params.targets[0] != "prune") {
+		return exitInternal, errors.New("expected either baseline create or baseline prune")
+	}
+	if params.baseline == "" {
+		return exitInternal, errors.New("missing baseline file, use --baseline")
+	}
+
+	scanned, err := scan(log, params)
+	if err != nil {
+		return exitInternal, err
+	}
+	report, err := scanned.report(false)
+	if err != nil {
+		return exitInternal, err
+	}
+
+	// entries of files which were not scanned (or could not be read) are kept as they are
+	config := contracts.BaselineConfig{
+		Root: scanned.root,
+	}
+	filename := baselinePath(params, scanned.root)
+	file, err := readBaseline(filename)
+	if err != nil {
+		return exitInternal, err
+	}
+	if file != nil {
+		config.Entries = file.Entries
+	} else if params.targets[0] == "prune" {
+		return exitInternal, fmt.Errorf("no baseline found at %s", filename)
+	}
+	baseline, err := gofixit.NewBaseline(log, config)
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while creating baseline (%w)", err)
+	}
+
+	results := report.Results
+	if params.targets[0] == "prune" {
+		match := baseline.Match(results, report.Files)
+		results = match.Baselined
+		fmt.Fprintf(os.Stderr, "gofixit: pruned %d fixed entry(ies) from %s\n", len(match.Fixed), filename)
+	}
+	entries := baseline.Record(results, report.Files)
+	err = writeBaseline(filename, entries)
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while writing baseline (%w)", err)
+	}
+	if params.targets[0] == "create" {
+		fmt.Fprintf(os.Stderr, "gofixit: recorded %d issue(s) in %s\n", len(report.Results), filename)
+	}
+	if len(scanned.failures) > 0 {
+		fmt.Fprintf(os.Stderr, "gofixit: %d file(s) could not be processed\n", len(scanned.failures))
+		return exitFileErrors, nil
+	}
+	return exitSuccess, nil
+}
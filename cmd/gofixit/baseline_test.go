@@ -0,0 +1,203 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gofixit "github.com/LouisBrunner/gofixit/src"
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func violating(path, prefix, content string) contracts.Result {
+	return contracts.Result{
+		Path: path,
+		Comment: contracts.ParsedComment{
+			Prefix:  prefix,
+			Content: content,
+		},
+		Violation: &contracts.Violation{
+			Rule:    contracts.RuleMissingExpiry,
+			Message: "missing expiry",
+		},
+	}
+}
+
+func Test_applyBaseline(t *testing.T) {
+	root := t.TempDir()
+	known := violating(filepath.Join(root, "src/main.c"), "TODO", ": known")
+	fresh := violating(filepath.Join(root, "src/main.c"), "TODO", ": new")
+	other := violating(filepath.Join(root, "lib/util.go"), "FIXME", ": other")
+
+	recorder, err := gofixit.NewBaseline(logrus.New(), contracts.BaselineConfig{Root: root})
+	if !assert.NoError(t, err) {
+		return
+	}
+	entries := recorder.Record([]contracts.Result{known, other}, []string{known.Path, other.Path})
+	if !assert.NoError(t, writeBaseline(filepath.Join(root, defaultBaseline), entries)) {
+		return
+	}
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "baseline.json")
+	if !assert.NoError(t, writeBaseline(filename, entries)) {
+		return
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	if !assert.NoError(t, os.WriteFile(invalid, []byte("{"), 0o644)) {
+		return
+	}
+
+	report := func(files []string, results ...contracts.Result) contracts.Report {
+		return contracts.Report{
+			Files:   files,
+			Results: results,
+			Summary: contracts.Summary{Violations: len(results)},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		baseline string
+		root     string
+		report   contracts.Report
+		want     contracts.Report
+		wantErr  bool
+	}{
+		{
+			name:     "does nothing without baseline",
+			baseline: "",
+			report:   report([]string{known.Path}, known),
+			want:     report([]string{known.Path}, known),
+		},
+		{
+			name:     "does nothing when the default baseline doesn't exist",
+			baseline: defaultBaseline,
+			root:     dir,
+			report:   report([]string{known.Path}, known),
+			want:     report([]string{known.Path}, known),
+		},
+		{
+			name:     "fails when another baseline doesn't exist",
+			baseline: filepath.Join(dir, "missing.json"),
+			report:   report([]string{known.Path}, known),
+			wantErr:  true,
+		},
+		{
+			name:     "reads the default baseline from the root",
+			baseline: defaultBaseline,
+			report:   report([]string{known.Path}, known),
+			want: contracts.Report{
+				Files: []string{known.Path},
+			},
+		},
+		{
+			name:     "removes known issues",
+			baseline: filename,
+			report:   report([]string{known.Path, other.Path}, known, fresh, other),
+			want: contracts.Report{
+				Files:   []string{known.Path, other.Path},
+				Results: []contracts.Result{fresh},
+				Summary: contracts.Summary{Violations: 1},
+			},
+		},
+		{
+			name:     "only considers the scanned files",
+			baseline: filename,
+			report:   report([]string{other.Path}, other),
+			want: contracts.Report{
+				Files: []string{other.Path},
+			},
+		},
+		{
+			name:     "fails on invalid baselines",
+			baseline: invalid,
+			report:   report([]string{known.Path}, known),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := &args{baseline: tt.baseline}
+			if tt.root == "" {
+				tt.root = root
+			}
+			err := applyBaseline(logrus.New(), params, tt.root, &tt.report)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, tt.report)
+			}
+		})
+	}
+}
+
+func Test_readBaseline(t *testing.T) {
+	hash := "028341fd63aa440621ddfd0bd54b6d15b1b0bf4fb0ab58efdda8be889cf5b569"
+
+	tests := []struct {
+		name    string
+		content string
+		want    *contracts.BaselineFile
+		wantErr bool
+	}{
+		{
+			name:    "reads entries",
+			content: `{"version": 1, "entries": [{"path": "src/main.c", "prefix": "TODO", "rule": "overdue", "hash": "` + hash + `"}]}`,
+			want: &contracts.BaselineFile{
+				Version: 1,
+				Entries: []contracts.BaselineEntry{{Path: "src/main.c", Prefix: "TODO", Rule: contracts.RuleOverdue, Hash: hash}},
+			},
+		},
+		{
+			name:    "rejects other versions",
+			content: `{"version": 2, "entries": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "rejects truncated hashes",
+			content: `{"version": 1, "entries": [{"path": "src/main.c", "prefix": "TODO", "rule": "overdue", "hash": "0283"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "rejects invalid hashes",
+			content: `{"version": 1, "entries": [{"path": "src/main.c", "prefix": "TODO", "rule": "overdue", "hash": "` + strings.Repeat("z", 64) + `"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "rejects entries without path",
+			content: `{"version": 1, "entries": [{"prefix": "TODO", "rule": "overdue", "hash": "` + hash + `"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "rejects entries without prefix",
+			content: `{"version": 1, "entries": [{"path": "src/main.c", "rule": "overdue", "hash": "` + hash + `"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "rejects entries without rule",
+			content: `{"version": 1, "entries": [{"path": "src/main.c", "prefix": "TODO", "hash": "` + hash + `"}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "baseline.json")
+			if !assert.NoError(t, os.WriteFile(filename, []byte(tt.content), 0o644)) {
+				return
+			}
+			got, err := readBaseline(filename)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
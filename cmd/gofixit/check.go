@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	gofixit "github.com/LouisBrunner/gofixit/src"
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+)
+
+func checkFlags() {
+	baselineFlags()
+	ownerFlag()
+}
+
+func runCheck(log *logrus.Logger, params *args) (int, error) {
+	scanned, err := scan(log, params)
+	if err != nil {
+		return exitInternal, err
+	}
+	report, err := scanned.report(false)
+	if err != nil {
+		return exitInternal, err
+	}
+	err = applyBaseline(log, params, scanned.root, &report)
+	if err != nil {
+		return exitInternal, err
+	}
+
+	reporter, err := gofixit.NewReporter(log, contracts.ReporterConfig{
+		Format:   params.format,
+		Output:   os.Stdout,
+		Template: params.template,
+		Now:      scanned.now,
+		Color:    useColor(os.Stdout),
+	})
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while creating reporter (%w)", err)
+	}
+	err = reporter.Report(report)
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while reporting results (%w)", err)
+	}
+	if summary := os.Getenv("GITHUB_STEP_SUMMARY"); summary != "" {
+		err = writeStepSummary(log, summary, report)
+		if err != nil {
+			return exitInternal, fmt.Errorf("failed while writing job summary (%w)", err)
+		}
+	}
+	if len(report.Errors) > 0 {
+		fmt.Fprintf(os.Stderr, "gofixit: %d file(s) could not be processed\n", len(report.Errors))
+	}
+
+	return scanned.exitCode(report), nil
+}
+
+// writeStepSummary appends a markdown version of the report to the GitHub Actions job summary
+func writeStepSummary(log *logrus.Logger, filename string, report contracts.Report) (err error) {
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	reporter, err := gofixit.NewReporter(log, contracts.ReporterConfig{
+		Format: contracts.FormatMarkdown,
+		Output: file,
+	})
+	if err != nil {
+		return err
+	}
+	return reporter.Report(report)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+)
+
+type command struct {
+	name        string
+	description string
+	// flags registers the settings specific to this command
+	flags func()
+	// defaultFormat is used when no Format is configured
+	defaultFormat func() contracts.Format
+	// targets is how many positional arguments the command accepts, e.g. the location given to snooze
+	targets int
+	run     func(log *logrus.Logger, params *args) (int, error)
+}
+
+// commands lists every subcommand, the first one is used when none is given
+var commands = []command{
+	{
+		name:          "check",
+		description:   "report comments which are overdue or break the configured rules",
+		flags:         checkFlags,
+		defaultFormat: terminalFormat,
+		run:           runCheck,
+	},
+	{
+		name:          "watch",
+		description:   "check files again every time they change, and at midnight, until interrupted",
+		flags:         watchFlags,
+		defaultFormat: terminalFormat,
+		run:           runWatch,
+	},
+	{
+		name:          "list",
+		description:   "print every matched comment, not only the ones breaking rules",
+		flags:         listFlags,
+		defaultFormat: listFormat,
+		run:           runList,
+	},
+	{
+		name:        "calendar",
+		description: "print an iCalendar file with an event on the expiry date of every dated comment",
+		flags:       listFlags,
+		run:         runCalendar,
+	},
+	{
+		name:          "stats",
+		description:   "print technical debt metrics, in total and per directory, extension and prefix",
+		defaultFormat: statsFormat,
+		run:           runStats,
+	},
+	{
+		name:        "fix",
+		description: "add an expiry date to comments without one, e.g. fix --add-expiry +90d",
+		flags:       fixFlags,
+		run:         runFix,
+	},
+	{
+		name:        "snooze",
+		description: "push back the expiry date of a comment, e.g. snooze path/to/file.go:42 --by 2w --reason \"...\"",
+		flags:       snoozeFlags,
+		targets:     1,
+		run:         runSnooze,
+	},
+	{
+		name:        "baseline",
+		description: "record the current issues so check ignores them, with baseline create, or remove the fixed ones, with baseline prune",
+		flags:       baselineFlags,
+		targets:     1,
+		run:         runBaseline,
+	},
+	{
+		name:        "report",
+		description: "write a static HTML page listing every comment with its status",
+		flags:       reportFlags,
+		run:         runReport,
+	},
+}
+
+// pickCommand finds the command given as first argument, defaulting to the first one when the arguments start
+// with a flag
+func pickCommand(arguments []string) (*command, []string, error) {
+	if len(arguments) == 0 || strings.HasPrefix(arguments[0], "-") {
+		return &commands[0], arguments, nil
+	}
+	for i := range commands {
+		if commands[i].name == arguments[0] {
+			return &commands[i], arguments[1:], nil
+		}
+	}
+	return nil, nil, fmt.Errorf("unknown command %q", arguments[0])
+}
+
+// checkTargets rejects the positional arguments a command doesn't accept, which are most likely a mistyped flag or
+// a command given after a flag
+func checkTargets(cmd *command, targets []string) error {
+	if len(targets) <= cmd.targets {
+		return nil
+	}
+	extra := targets[cmd.targets]
+	for _, other := range commands {
+		if other.name == extra {
+			return fmt.Errorf("unexpected argument %q, the command must come before any flag, e.g. gofixit %s --strict", extra, extra)
+		}
+	}
+	return fmt.Errorf("unexpected argument %q, %s doesn't take any more arguments", extra, cmd.name)
+}
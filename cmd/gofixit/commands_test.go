@@ -0,0 +1,103 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pickCommand(t *testing.T) {
+	tests := []struct {
+		name          string
+		arguments     []string
+		wantCommand   string
+		wantArguments []string
+		wantErr       bool
+	}{
+		{
+			name:        "defaults to check",
+			wantCommand: "check",
+		},
+		{
+			name:          "defaults to check with flags",
+			arguments:     []string{"--strict", "list"},
+			wantCommand:   "check",
+			wantArguments: []string{"--strict", "list"},
+		},
+		{
+			name:          "picks the command",
+			arguments:     []string{"list", "--strict"},
+			wantCommand:   "list",
+			wantArguments: []string{"--strict"},
+		},
+		{
+			name:      "rejects unknown commands",
+			arguments: []string{"chek"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, arguments, err := pickCommand(tt.arguments)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.wantCommand, cmd.name)
+				assert.Equal(t, tt.wantArguments, arguments)
+			}
+		})
+	}
+}
+
+func Test_checkTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		targets []string
+		wantErr string
+	}{
+		{
+			name:    "accepts no targets",
+			command: "check",
+		},
+		{
+			name:    "accepts expected targets",
+			command: "snooze",
+			targets: []string{"main.go:42"},
+		},
+		{
+			name:    "rejects commands given after a flag",
+			command: "check",
+			targets: []string{"list"},
+			wantErr: `unexpected argument "list", the command must come before any flag, e.g. gofixit list --strict`,
+		},
+		{
+			name:    "rejects unexpected targets",
+			command: "check",
+			targets: []string{"chek"},
+			wantErr: `unexpected argument "chek", check doesn't take any more arguments`,
+		},
+		{
+			name:    "rejects extra targets",
+			command: "snooze",
+			targets: []string{"main.go:42", "main.go:43"},
+			wantErr: `unexpected argument "main.go:43", snooze doesn't take any more arguments`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, _, err := pickCommand([]string{tt.command})
+			if !assert.NoError(t, err) {
+				return
+			}
+			err = checkTargets(cmd, tt.targets)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	gofixit "github.com/LouisBrunner/gofixit/src"
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+)
+
+func fixFlags() {
+	addDefault([]string{"Add", "Expiry"}, "", pflag.String, "expiry added to comments without one, relative to today (e.g. +90d) or as a date using DateLayout")
+	dryRunFlag()
+}
+
+func dryRunFlag() {
+	addDefault([]string{"Dry", "Run"}, false, pflag.Bool, "print a unified diff of the changes instead of modifying files")
+}
+
+// expiryFrom reads a relative duration from now, or an absolute date
+func expiryFrom(now time.Time, value string, layout string) (time.Time, error) {
+	duration, err := utils.ParseDuration(value)
+	if err == nil {
+		return now.Add(duration), nil
+	}
+	date, dateErr := time.Parse(layout, value)
+	if dateErr != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry %q, expected a duration like +90d or a date like %s", value, layout)
+	}
+	return date, nil
+}
+
+func runFix(log *logrus.Logger, params *args) (int, error) {
+	if params.addExpiry == "" {
+		return exitInternal, errors.New("missing expiry, use --add-expiry")
+	}
+	if params.rev != "" {
+		return exitInternal, errors.New("cannot fix files read from a git revision")
+	}
+	// archive members cannot be rewritten
+	params.scanArchives = false
+
+	scanned, err := scan(log, params)
+	if err != nil {
+		return exitInternal, err
+	}
+	expiry, err := expiryFrom(scanned.now, params.addExpiry, params.dateLayout)
+	if err != nil {
+		return exitInternal, err
+	}
+
+	rewriter, err := gofixit.NewRewriter(log, contracts.RewriterConfig{
+		DryRun: params.dryRun,
+		Diff:   os.Stdout,
+	})
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while creating rewriter (%w)", err)
+	}
+
+	rewrite := func(comment contracts.ParsedComment) contracts.LineRewriter {
+		return func(line string) (string, error) {
+			return scanned.parser.Rewrite(line, expiry, comment.Metadata)
+		}
+	}
+	comments, files := 0, 0
+	for _, entry := range utils.SortedMap(scanned.parsed) {
+		lines := map[uint]contracts.LineRewriter{}
+		for _, comment := range entry.Value.comments {
+			if comment.Expiry == nil && comment.InvalidExpiry == "" {
+				lines[comment.LineNumber] = rewrite(comment)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		changed, err := rewriter.Rewrite(entry.Key, lines)
+		if err != nil {
+			return exitInternal, fmt.Errorf("failed while fixing %s (%w)", entry.Key, err)
+		}
+		if len(changed) > 0 {
+			comments += len(changed)
+			files += 1
+		}
+	}
+
+	verb := "added"
+	if params.dryRun {
+		verb = "would add"
+	}
+	fmt.Fprintf(os.Stderr, "gofixit: %s expiry %s to %d comment(s) in %d file(s)\n", verb, expiry.Format(params.dateLayout), comments, files)
+	if len(scanned.failures) > 0 {
+		fmt.Fprintf(os.Stderr, "gofixit: %d file(s) could not be processed\n", len(scanned.failures))
+		return exitFileErrors, nil
+	}
+	return exitSuccess, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_expiryFrom(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "adds a duration",
+			value: "+90d",
+			want:  now.AddDate(0, 0, 90),
+		},
+		{
+			name:  "adds a duration without sign",
+			value: "2w",
+			want:  now.AddDate(0, 0, 14),
+		},
+		{
+			name:  "reads a date",
+			value: "2025-01-31",
+			want:  time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "rejects anything else",
+			value:   "soon",
+			wantErr: true,
+		},
+		{
+			name:    "rejects dates in another layout",
+			value:   "31/01/2025",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expiryFrom(now, tt.value, "2006-01-02")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
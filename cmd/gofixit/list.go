@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	gofixit "github.com/LouisBrunner/gofixit/src"
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+)
+
+type inventoryFilters struct {
+	prefixes       []string
+	owners         []string
+	expired        bool
+	undated        bool
+	expiringWithin time.Duration
+}
+
+func listFlags() {
+	addDefault([]string{"Prefix"}, []string{}, pflag.StringSlice, "only list comments using one of these prefixes")
+	ownerFlag()
+	addDefault([]string{"Expired"}, false, pflag.Bool, "only list overdue comments (can be combined with --undated and --expiring-within)")
+	addDefault([]string{"Undated"}, false, pflag.Bool, "only list comments without an expiry date")
+	addDefault([]string{"Expiring", "Within"}, "", pflag.String, "only list comments expiring within this duration, e.g. 30d or 2w")
+	addDefault([]string{"Sort"}, "path", pflag.String, "sort comments by path, date or owner")
+}
+
+func ownerFlag() {
+	addDefault([]string{"Owner"}, []string{}, pflag.StringSlice, "only keep comments assigned to one of these owners, with TODO(owner) or through CODEOWNERS, e.g. @org/team")
+}
+
+func listFormat() contracts.Format {
+	return contracts.FormatTable
+}
+
+func containsFold(values []string, needle string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownedBy checks whether a result is assigned to one of the owners, either in the comment or through CODEOWNERS
+func ownedBy(owners []string, result contracts.Result) bool {
+	if containsFold(owners, result.Comment.Owner) {
+		return true
+	}
+	for _, owner := range result.CodeOwners {
+		if containsFold(owners, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// keep checks a result against the filters, the status filters (expired, undated and expiring within) are
+// alternatives while the others must all match
+func (me inventoryFilters) keep(now time.Time, result contracts.Result) bool {
+	comment := result.Comment
+	if len(me.prefixes) > 0 && !containsFold(me.prefixes, comment.Prefix) {
+		return false
+	}
+	if len(me.owners) > 0 && !ownedBy(me.owners, result) {
+		return false
+	}
+	if !me.expired && !me.undated && me.expiringWithin == 0 {
+		return true
+	}
+	switch {
+	case me.expired && result.Status == contracts.StatusOverdue:
+		return true
+	case me.undated && comment.Expiry == nil && comment.InvalidExpiry == "":
+		return true
+	case me.expiringWithin > 0 && comment.Expiry != nil && !now.After(*comment.Expiry):
+		return comment.Expiry.Sub(now) <= me.expiringWithin
+	}
+	return false
+}
+
+// lessBy returns how results are ordered for each sort key, they come sorted by path and line already
+var lessBy = map[string]func(a, b contracts.Result) bool{
+	"path": func(a, b contracts.Result) bool {
+		return false
+	},
+	"date": func(a, b contracts.Result) bool {
+		if a.Comment.Expiry == nil || b.Comment.Expiry == nil {
+			return a.Comment.Expiry != nil && b.Comment.Expiry == nil
+		}
+		return a.Comment.Expiry.Before(*b.Comment.Expiry)
+	},
+	"owner": func(a, b contracts.Result) bool {
+		if a.Comment.Owner == "" || b.Comment.Owner == "" {
+			return a.Comment.Owner != "" && b.Comment.Owner == ""
+		}
+		return a.Comment.Owner < b.Comment.Owner
+	},
+}
+
+// inventory lists every comment matching the filters, in the requested order
+func inventory(log *logrus.Logger, params *args) (*scanned, contracts.Report, error) {
+	less, found := lessBy[params.sortBy]
+	if !found {
+		return nil, contracts.Report{}, fmt.Errorf("unknown sort %q, must be one of path, date or owner", params.sortBy)
+	}
+
+	scanned, err := scan(log, params)
+	if err != nil {
+		return nil, contracts.Report{}, err
+	}
+	report, err := scanned.report(true)
+	if err != nil {
+		return nil, contracts.Report{}, err
+	}
+
+	kept := report.Results[:0]
+	for _, result := range report.Results {
+		if params.filters.keep(scanned.now, result) {
+			kept = append(kept, result)
+		}
+	}
+	report.Results = kept
+	sort.SliceStable(report.Results, func(i, j int) bool {
+		return less(report.Results[i], report.Results[j])
+	})
+	return scanned, report, nil
+}
+
+func runList(log *logrus.Logger, params *args) (int, error) {
+	return printInventory(log, params, params.format)
+}
+
+func runCalendar(log *logrus.Logger, params *args) (int, error) {
+	return printInventory(log, params, contracts.FormatICal)
+}
+
+// printInventory prints every matched comment to stdout
+func printInventory(log *logrus.Logger, params *args, format contracts.Format) (int, error) {
+	scanned, report, err := inventory(log, params)
+	if err != nil {
+		return exitInternal, err
+	}
+
+	reporter, err := gofixit.NewReporter(log, contracts.ReporterConfig{
+		Format:   format,
+		Output:   os.Stdout,
+		Template: params.template,
+		Now:      scanned.now,
+		Color:    useColor(os.Stdout),
+	})
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while creating reporter (%w)", err)
+	}
+	err = reporter.Report(report)
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while reporting results (%w)", err)
+	}
+	if len(report.Errors) > 0 {
+		fmt.Fprintf(os.Stderr, "gofixit: %d file(s) could not be processed\n", len(report.Errors))
+		return exitFileErrors, nil
+	}
+	return exitSuccess, nil
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+func listed(prefix, owner string, expiry *time.Time, status contracts.Status) contracts.Result {
+	return contracts.Result{
+		Path: "main.go",
+		Comment: contracts.ParsedComment{
+			Prefix: prefix,
+			Owner:  owner,
+			Expiry: expiry,
+		},
+		Status: status,
+	}
+}
+
+func Test_inventoryFilters_keep(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	past := now.AddDate(0, 0, -1)
+	soon := now.AddDate(0, 0, 5)
+	later := now.AddDate(0, 2, 0)
+
+	owned := listed("TODO", "", &soon, contracts.StatusOK)
+	owned.CodeOwners = []string{"@org/payments"}
+	invalid := listed("TODO", "", nil, contracts.StatusInvalid)
+	invalid.Comment.InvalidExpiry = "someday"
+
+	tests := []struct {
+		name    string
+		filters inventoryFilters
+		result  contracts.Result
+		want    bool
+	}{
+		{
+			name:   "keeps everything without filters",
+			result: listed("TODO", "", nil, contracts.StatusUndated),
+			want:   true,
+		},
+		{
+			name:    "matches prefixes without case",
+			filters: inventoryFilters{prefixes: []string{"fixme"}},
+			result:  listed("FIXME", "", nil, contracts.StatusOK),
+			want:    true,
+		},
+		{
+			name:    "rejects other prefixes",
+			filters: inventoryFilters{prefixes: []string{"FIXME"}},
+			result:  listed("TODO", "", nil, contracts.StatusUndated),
+		},
+		{
+			name:    "matches the owner of the comment",
+			filters: inventoryFilters{owners: []string{"@louis"}},
+			result:  listed("TODO", "@Louis", nil, contracts.StatusOK),
+			want:    true,
+		},
+		{
+			name:    "matches code owners",
+			filters: inventoryFilters{owners: []string{"@org/payments"}},
+			result:  owned,
+			want:    true,
+		},
+		{
+			name:    "rejects other owners",
+			filters: inventoryFilters{owners: []string{"@org/web"}},
+			result:  owned,
+		},
+		{
+			name:    "keeps overdue comments",
+			filters: inventoryFilters{expired: true},
+			result:  listed("TODO", "", &past, contracts.StatusOverdue),
+			want:    true,
+		},
+		{
+			name:    "rejects comments which are not overdue",
+			filters: inventoryFilters{expired: true},
+			result:  listed("TODO", "", &soon, contracts.StatusOK),
+		},
+		{
+			name:    "keeps undated comments",
+			filters: inventoryFilters{undated: true},
+			result:  listed("TODO", "", nil, contracts.StatusUndated),
+			want:    true,
+		},
+		{
+			name:    "does not consider invalid dates as undated",
+			filters: inventoryFilters{undated: true},
+			result:  invalid,
+		},
+		{
+			name:    "keeps comments expiring soon",
+			filters: inventoryFilters{expiringWithin: 7 * 24 * time.Hour},
+			result:  listed("TODO", "", &soon, contracts.StatusOK),
+			want:    true,
+		},
+		{
+			name:    "rejects comments expiring later",
+			filters: inventoryFilters{expiringWithin: 7 * 24 * time.Hour},
+			result:  listed("TODO", "", &later, contracts.StatusOK),
+		},
+		{
+			name:    "rejects comments which already expired when expiring within",
+			filters: inventoryFilters{expiringWithin: 7 * 24 * time.Hour},
+			result:  listed("TODO", "", &past, contracts.StatusOverdue),
+		},
+		{
+			name:    "combines status filters as alternatives",
+			filters: inventoryFilters{expired: true, undated: true},
+			result:  listed("TODO", "", nil, contracts.StatusUndated),
+			want:    true,
+		},
+		{
+			name:    "requires the other filters along status filters",
+			filters: inventoryFilters{prefixes: []string{"FIXME"}, undated: true},
+			result:  listed("TODO", "", nil, contracts.StatusUndated),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filters.keep(now, tt.result))
+		})
+	}
+}
+
+func Test_lessBy(t *testing.T) {
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		sortBy  string
+		results []contracts.Result
+		want    []string
+	}{
+		{
+			name:   "keeps the order by path",
+			sortBy: "path",
+			results: []contracts.Result{
+				listed("B", "@b", &late, contracts.StatusOK),
+				listed("A", "@a", &early, contracts.StatusOK),
+			},
+			want: []string{"B", "A"},
+		},
+		{
+			name:   "sorts by date with undated comments last",
+			sortBy: "date",
+			results: []contracts.Result{
+				listed("A", "", nil, contracts.StatusOK),
+				listed("B", "", &late, contracts.StatusOK),
+				listed("C", "", nil, contracts.StatusOK),
+				listed("D", "", &early, contracts.StatusOK),
+			},
+			want: []string{"D", "B", "A", "C"},
+		},
+		{
+			name:   "sorts by owner with unassigned comments last",
+			sortBy: "owner",
+			results: []contracts.Result{
+				listed("A", "", nil, contracts.StatusOK),
+				listed("B", "@zoe", nil, contracts.StatusOK),
+				listed("C", "@adam", nil, contracts.StatusOK),
+				listed("D", "@adam", nil, contracts.StatusOK),
+			},
+			want: []string{"C", "D", "B", "A"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			less := lessBy[tt.sortBy]
+			sort.SliceStable(tt.results, func(i, j int) bool {
+				return less(tt.results[i], tt.results[j])
+			})
+			got := []string{}
+			for _, result := range tt.results {
+				got = append(got, result.Comment.Prefix)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
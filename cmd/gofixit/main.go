@@ -2,89 +2,45 @@ package main
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
-	"time"
 
-	gofixit "github.com/LouisBrunner/gofixit/src"
-	"github.com/LouisBrunner/gofixit/src/contracts"
-	"github.com/LouisBrunner/gofixit/src/utils"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	exitSuccess = iota
+	exitIssues
+	exitInternal
+	exitFileErrors
+)
+
 func main() {
-	success, err := work()
+	code, err := work()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "gofixit: %s\n", err.Error())
-		os.Exit(2)
-	}
-	if !success {
-		os.Exit(1)
+		os.Exit(exitInternal)
 	}
+	os.Exit(code)
 }
 
-func work() (bool, error) {
-	params, err := getArgs()
+func work() (int, error) {
+	cmd, arguments, err := pickCommand(os.Args[1:])
 	if err != nil {
-		return false, fmt.Errorf("failed to read configuration: %w", err)
+		return exitInternal, err
 	}
 
-	log := logrus.New()
-	log.SetLevel(params.loggingLevel)
-	log.SetOutput(os.Stderr)
-
-	parser, err := gofixit.NewParser(log, contracts.ParsingConfig{
-		CommentPrefixes: params.commentPrefixes,
-		Prefixes:        params.prefixes,
-		ExpiryPattern:   params.expiryPattern,
-		CaseSensitive:   !params.caseInsensitive,
-		DateLayout:      params.dateLayout,
-	})
+	params, err := getArgs(cmd, arguments)
 	if err != nil {
-		return false, fmt.Errorf("failed while creating parser (%w)", err)
+		return exitInternal, fmt.Errorf("failed to read configuration: %w", err)
 	}
-
-	enforcer, err := gofixit.NewEnforcer(log, contracts.EnforcerConfig{
-		Strict: params.strict,
-		Now:    time.Now(),
-	})
+	err = checkTargets(cmd, params.targets)
 	if err != nil {
-		return false, fmt.Errorf("failed while creating enforcer (%w)", err)
-	}
-
-	glue := func(filepath string) ([]contracts.ParsedComment, error) {
-		// FIXME: should really be streaming files better than this
-		content, err := ioutil.ReadFile(filepath)
-		if err != nil {
-			return nil, err
-		}
-		return parser.Parse(string(content))
+		return exitInternal, err
 	}
 
-	processor, err := gofixit.NewFilesProcessor(log, contracts.FilesProcessorConfig[[]contracts.ParsedComment]{
-		Processor:            glue,
-		Recursive:            params.recursive,
-		FilesExcludePatterns: params.filesExcludePattern,
-	})
-	if err != nil {
-		return false, fmt.Errorf("failed while creating processor (%w)", err)
-	}
-
-	parsed, err := processor.ProcessFiles(params.files)
-	if err != nil {
-		return false, fmt.Errorf("failed while parsing files (%w)", err)
-	}
+	log := logrus.New()
+	log.SetLevel(params.loggingLevel)
+	log.SetOutput(os.Stderr)
 
-	hadError := false
-	for _, entry := range utils.SortedMap(parsed) {
-		for _, comment := range entry.Value {
-			err := enforcer.Check(comment)
-			if err == nil {
-				continue
-			}
-			fmt.Printf("%s:%d %s\n", entry.Key, comment.LineNumber, err.Error())
-			hadError = true
-		}
-	}
-	return !hadError, nil
+	return cmd.run(log, params)
 }
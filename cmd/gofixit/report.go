@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	gofixit "github.com/LouisBrunner/gofixit/src"
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+)
+
+func reportFlags() {
+	addDefault([]string{"Html"}, "", pflag.String, "file where the HTML report is written")
+}
+
+func runReport(log *logrus.Logger, params *args) (code int, err error) {
+	if params.html == "" {
+		return exitInternal, errors.New("missing output file, use --html")
+	}
+
+	scanned, err := scan(log, params)
+	if err != nil {
+		return exitInternal, err
+	}
+	report, err := scanned.report(true)
+	if err != nil {
+		return exitInternal, err
+	}
+
+	file, err := os.Create(params.html)
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while creating report (%w)", err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if err == nil && closeErr != nil {
+			code, err = exitInternal, fmt.Errorf("failed while writing report (%w)", closeErr)
+		}
+	}()
+
+	reporter, err := gofixit.NewReporter(log, contracts.ReporterConfig{
+		Format: contracts.FormatHTML,
+		Output: file,
+		Now:    scanned.now,
+	})
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while creating reporter (%w)", err)
+	}
+	err = reporter.Report(report)
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while reporting results (%w)", err)
+	}
+	if len(scanned.failures) > 0 {
+		fmt.Fprintf(os.Stderr, "gofixit: %d file(s) could not be processed\n", len(scanned.failures))
+		return exitFileErrors, nil
+	}
+	return exitSuccess, nil
+}
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	gofixit "github.com/LouisBrunner/gofixit/src"
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// parsedFile holds the comments and suppression directives found in a file
+type parsedFile struct {
+	comments     []contracts.ParsedComment
+	suppressions []contracts.Suppression
+}
+
+// scanned holds every comment parsed from the configured files, alongside the files which could not be processed
+type scanned struct {
+	now            time.Time
+	parser         contracts.Parser
+	enforcerConfig contracts.EnforcerConfig
+	enforcer       contracts.Enforcer
+	processor      contracts.FilesProcessor[parsedFile]
+	codeOwners     contracts.CodeOwners
+	// showBlame keeps the blame of comments in reports, it is otherwise only used to check ImplicitExpiry
+	showBlame bool
+	// ownerFilter restricts reports to the comments and files assigned to one of these owners, when any
+	ownerFilter []string
+	root        string
+	parsed      map[string]parsedFile
+	failures    contracts.FilesErrors
+}
+
+// findRoot returns the closest directory containing .git, or the current directory outside of a repository
+func findRoot() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for dir := cwd; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, nil
+		}
+		if dir == filepath.Dir(dir) {
+			return cwd, nil
+		}
+	}
+}
+
+func newParser(log *logrus.Logger, params *args) (contracts.Parser, error) {
+	parser, err := gofixit.NewParser(log, contracts.ParsingConfig{
+		CommentPrefixes: params.commentPrefixes,
+		Prefixes:        params.prefixes,
+		ExpiryPattern:   params.expiryPattern,
+		CaseSensitive:   !params.caseInsensitive,
+		DateLayout:      params.dateLayout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed while creating parser (%w)", err)
+	}
+	return parser, nil
+}
+
+func scan(log *logrus.Logger, params *args) (*scanned, error) {
+	parser, err := newParser(log, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.maxSnoozes > 0 && !storesMetadata(params.expiryPattern) {
+		return nil, errors.New("MaxSnoozes requires ExpiryPattern to contain {{.Meta}}, where the number of snoozes is kept")
+	}
+
+	now := time.Now()
+	enforcerConfig := contracts.EnforcerConfig{
+		Strict:                   params.strict,
+		Now:                      now,
+		WarningPeriod:            params.warningPeriod,
+		MaxSnoozes:               params.maxSnoozes,
+		ImplicitExpiry:           params.implicitExpiry,
+		ReportUnusedSuppressions: params.reportUnused,
+	}
+	enforcer, err := gofixit.NewEnforcer(log, enforcerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed while creating enforcer (%w)", err)
+	}
+
+	var blamer contracts.GitBlamer
+	if params.blame || params.implicitExpiry > 0 {
+		blamer, err = gofixit.NewGitBlamer(log, contracts.GitBlamerConfig{
+			Revision: params.rev,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed while creating blamer (%w)", err)
+		}
+	}
+
+	glue := func(filepath string, file io.Reader) (parsedFile, error) {
+		// FIXME: should really be streaming files better than this
+		content, err := io.ReadAll(file)
+		if err != nil {
+			return parsedFile{}, err
+		}
+		comments, err := parser.Parse(string(content))
+		if err != nil {
+			return parsedFile{}, err
+		}
+		suppressions, err := parser.ParseSuppressions(string(content))
+		if err != nil {
+			return parsedFile{}, err
+		}
+		if blamer != nil && len(comments) > 0 {
+			blame(log, blamer, filepath, comments, params.blame)
+		}
+		return parsedFile{
+			comments:     comments,
+			suppressions: suppressions,
+		}, nil
+	}
+
+	root, err := findRoot()
+	if err != nil {
+		return nil, err
+	}
+	codeOwners, err := newCodeOwners(log, params, root)
+	if err != nil {
+		return nil, err
+	}
+
+	var fsys fs.FS
+	if params.rev != "" {
+		fsys, err = gofixit.NewGitFS(log, contracts.GitFSConfig{
+			Revision: params.rev,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed while reading revision (%w)", err)
+		}
+	}
+
+	processor, err := gofixit.NewFilesProcessor(log, contracts.FilesProcessorConfig[parsedFile]{
+		Processor:            glue,
+		Recursive:            params.recursive,
+		FilesExcludePatterns: params.filesExcludePattern,
+		FS:                   fsys,
+		ScanArchives:         params.scanArchives,
+		KeepGoing:            params.keepGoing,
+		SkipSymlinks:         !params.followSymlinks,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed while creating processor (%w)", err)
+	}
+
+	parsed, err := processor.ProcessFiles(params.files)
+	if closer, ok := fsys.(io.Closer); ok {
+		closer.Close()
+	}
+	var failures contracts.FilesErrors
+	if err != nil && !errors.As(err, &failures) {
+		return nil, fmt.Errorf("failed while parsing files (%w)", err)
+	}
+
+	return &scanned{
+		now:            now,
+		parser:         parser,
+		enforcerConfig: enforcerConfig,
+		enforcer:       enforcer,
+		processor:      processor,
+		codeOwners:     codeOwners,
+		showBlame:      params.blame,
+		ownerFilter:    params.filters.owners,
+		root:           root,
+		parsed:         parsed,
+		failures:       failures,
+	}, nil
+}
+
+// within tells if a file (or archive member) was found at path or inside it
+func within(file, path string) bool {
+	file, _, _ = strings.Cut(file, "!/")
+	file = filepath.Clean(file)
+	return file == path || strings.HasPrefix(file, path+string(filepath.Separator))
+}
+
+// rescan parses the given files or directories again, forgetting the ones which were removed, it tells if any
+// comment, suppression or failure changed
+func (me *scanned) rescan(paths []string) bool {
+	before := me.snapshot(paths)
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		for file := range me.parsed {
+			if within(file, path) {
+				delete(me.parsed, file)
+			}
+		}
+		for file := range me.failures {
+			if within(file, path) {
+				delete(me.failures, file)
+			}
+		}
+		if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		parsed, err := me.processor.ProcessFiles([]string{path})
+		var failures contracts.FilesErrors
+		if errors.As(err, &failures) {
+			for file, err := range failures {
+				me.fail(file, err)
+			}
+		} else if err != nil {
+			me.fail(path, err)
+		}
+		for file, result := range parsed {
+			me.parsed[file] = result
+		}
+	}
+	return !reflect.DeepEqual(before, me.snapshot(paths))
+}
+
+// snapshot copies what is known about the files found in paths, files without any comment or suppression are left
+// out as creating or removing them doesn't change anything
+func (me *scanned) snapshot(paths []string) map[string]any {
+	out := map[string]any{}
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		for file, result := range me.parsed {
+			if len(result.comments) == 0 && len(result.suppressions) == 0 {
+				continue
+			}
+			if within(file, path) {
+				out[file] = result
+			}
+		}
+		for file, err := range me.failures {
+			if within(file, path) {
+				out[file] = err.Error()
+			}
+		}
+	}
+	return out
+}
+
+func (me *scanned) fail(file string, err error) {
+	if me.failures == nil {
+		me.failures = contracts.FilesErrors{}
+	}
+	me.failures[file] = err
+}
+
+// reevaluate checks comments against a new date from now on
+func (me *scanned) reevaluate(log *logrus.Logger, now time.Time) error {
+	config := me.enforcerConfig
+	config.Now = now
+	enforcer, err := gofixit.NewEnforcer(log, config)
+	if err != nil {
+		return fmt.Errorf("failed while creating enforcer (%w)", err)
+	}
+	me.now = now
+	me.enforcerConfig = config
+	me.enforcer = enforcer
+	return nil
+}
+
+// newCodeOwners reads CODEOWNERS from the same revision as the files being checked
+func newCodeOwners(log *logrus.Logger, params *args, root string) (contracts.CodeOwners, error) {
+	config := contracts.CodeOwnersConfig{
+		Directory: root,
+	}
+	if params.rev != "" {
+		fsys, err := gofixit.NewGitFS(log, contracts.GitFSConfig{
+			Directory: root,
+			Revision:  params.rev,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed while reading revision (%w)", err)
+		}
+		if closer, ok := fsys.(io.Closer); ok {
+			defer closer.Close()
+		}
+		config.FS = fsys
+	}
+	codeOwners, err := gofixit.NewCodeOwners(log, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed while reading code owners (%w)", err)
+	}
+	return codeOwners, nil
+}
+
+// blame attaches the commit which last changed each comment, files outside of git (or in archives) are skipped, the
+// author is used as owner of comments without one when ownerFallback is set
+func blame(log *logrus.Logger, blamer contracts.GitBlamer, filepath string, comments []contracts.ParsedComment, ownerFallback bool) {
+	blames, err := blamer.Blame(filepath)
+	if err != nil {
+		log.Warnf("could not blame %s: %v", filepath, err)
+		return
+	}
+	for i := range comments {
+		if line, found := blames[comments[i].LineNumber]; found {
+			comments[i].Blame = &line
+			if ownerFallback && comments[i].Owner == "" {
+				comments[i].Owner = line.Author
+			}
+		}
+	}
+}
+
+// owners finds the owners of a file according to CODEOWNERS, files in archives belong to their archive
+func (me *scanned) owners(path string) []string {
+	path, _, _ = strings.Cut(path, "!/")
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return nil
+	}
+	relative, err := filepath.Rel(me.root, absolute)
+	if err != nil || strings.HasPrefix(relative, "..") {
+		return nil
+	}
+	return me.codeOwners.Owners(filepath.ToSlash(relative))
+}
+
+// assigned applies the owner filter, a file is assigned through CODEOWNERS (given as CodeOwners) and a comment
+// either through its file or its own owner
+func (me *scanned) assigned(result contracts.Result) bool {
+	return len(me.ownerFilter) == 0 || ownedBy(me.ownerFilter, result)
+}
+
+// report checks every comment, keeping all of them as results when all is set or only the violations otherwise
+// (including the ones about suppressions, which are not comments)
+func (me *scanned) report(all bool) (contracts.Report, error) {
+	return me.reportOn(all, nil)
+}
+
+// reportOn is report restricted to the files found in paths, or every file when paths is nil, the summary only
+// counts what passes the owner filter
+func (me *scanned) reportOn(all bool, paths []string) (contracts.Report, error) {
+	selected := func(file string) bool {
+		if paths == nil {
+			return true
+		}
+		for _, path := range paths {
+			if within(file, filepath.Clean(path)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	report := contracts.Report{
+		Errors: contracts.FilesErrors{},
+	}
+	for file, err := range me.failures {
+		if selected(file) && me.assigned(contracts.Result{CodeOwners: me.owners(file)}) {
+			report.Errors[file] = err
+		}
+	}
+	report.Summary.Errors = len(report.Errors)
+	report.Summary.Files = len(report.Errors)
+
+	for _, entry := range utils.SortedMap(me.parsed) {
+		if !selected(entry.Key) {
+			continue
+		}
+		owners := me.owners(entry.Key)
+		// comments of other files can still be assigned to one of the owners explicitly
+		owned := me.assigned(contracts.Result{CodeOwners: owners})
+		results := make([]contracts.Result, 0, len(entry.Value.comments))
+		for _, comment := range entry.Value.comments {
+			result := contracts.Result{
+				Path:       entry.Key,
+				Comment:    comment,
+				CodeOwners: owners,
+			}
+			if !owned && !me.assigned(result) {
+				continue
+			}
+			report.Summary.Comments += 1
+			result.Status = me.enforcer.Status(comment)
+			err := me.enforcer.Check(comment)
+			if err != nil && !errors.As(err, &result.Violation) {
+				return report, fmt.Errorf("failed while checking %s:%d (%w)", entry.Key, comment.LineNumber, err)
+			}
+			results = append(results, result)
+		}
+		if !owned && len(results) == 0 {
+			continue
+		}
+		report.Summary.Files += 1
+		report.Files = append(report.Files, entry.Key)
+
+		results, problems := me.enforcer.Suppress(entry.Key, results, entry.Value.suppressions)
+		if !owned {
+			problems = nil
+		}
+		if !all {
+			results = append(results, problems...)
+			sort.SliceStable(results, func(i, j int) bool {
+				return results[i].Comment.LineNumber < results[j].Comment.LineNumber
+			})
+		}
+		for _, result := range results {
+			result.CodeOwners = owners
+			if !me.showBlame {
+				result.Comment.Blame = nil
+			}
+			if result.Violation != nil {
+				report.Summary.Violations += 1
+			}
+			if all || result.Violation != nil {
+				report.Results = append(report.Results, result)
+			}
+		}
+	}
+	return report, nil
+}
+
+// exitCode is shared by commands which fail on violations
+func (me *scanned) exitCode(report contracts.Report) int {
+	if report.Summary.Violations > 0 {
+		return exitIssues
+	}
+	if len(report.Errors) > 0 {
+		return exitFileErrors
+	}
+	return exitSuccess
+}
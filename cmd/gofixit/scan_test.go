@@ -0,0 +1,267 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gofixit "github.com/LouisBrunner/gofixit/src"
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_within(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		path string
+		want bool
+	}{
+		{
+			name: "matches the file itself",
+			file: "src/main.go",
+			path: "src/main.go",
+			want: true,
+		},
+		{
+			name: "matches files in the directory",
+			file: "src/sub/main.go",
+			path: "src",
+			want: true,
+		},
+		{
+			name: "matches archive members",
+			file: "lib/vendor.zip!/main.go",
+			path: "lib/vendor.zip",
+			want: true,
+		},
+		{
+			name: "cleans the file",
+			file: "./src/main.go",
+			path: "src",
+			want: true,
+		},
+		{
+			name: "does not match siblings sharing a prefix",
+			file: "src2/main.go",
+			path: "src",
+		},
+		{
+			name: "does not match parents",
+			file: "src",
+			path: "src/main.go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, within(tt.file, tt.path))
+		})
+	}
+}
+
+func Test_rescan(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(root, name)
+		if !assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755)) {
+			t.FailNow()
+		}
+		if !assert.NoError(t, os.WriteFile(path, []byte(content), 0o644)) {
+			t.FailNow()
+		}
+		return path
+	}
+	contentOf := func(file parsedFile) string {
+		return file.comments[0].Content
+	}
+
+	processor, err := gofixit.NewFilesProcessor(logrus.New(), contracts.FilesProcessorConfig[parsedFile]{
+		Processor: func(filepath string, file io.Reader) (parsedFile, error) {
+			content, err := io.ReadAll(file)
+			if len(content) == 0 {
+				return parsedFile{}, err
+			}
+			return parsedFile{
+				comments: []contracts.ParsedComment{{Content: string(content)}},
+			}, err
+		},
+		Recursive: true,
+		KeepGoing: true,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	a := write("a.go", "a")
+	b := write("b.go", "b")
+	parsed, err := processor.ProcessFiles([]string{root})
+	if !assert.NoError(t, err) {
+		return
+	}
+	subject := &scanned{
+		processor: processor,
+		parsed:    parsed,
+	}
+
+	assert.False(t, subject.rescan([]string{a}), "nothing changed")
+
+	write("a.go", "a2")
+	assert.True(t, subject.rescan([]string{a}), "file changed")
+	assert.Equal(t, "a2", contentOf(subject.parsed[a]))
+	assert.Equal(t, "b", contentOf(subject.parsed[b]))
+
+	assert.NoError(t, os.Remove(b))
+	assert.True(t, subject.rescan([]string{b}), "file removed")
+	assert.NotContains(t, subject.parsed, b)
+
+	empty := write("empty.go", "")
+	assert.False(t, subject.rescan([]string{empty}), "file without comments created")
+	assert.NoError(t, os.Remove(empty))
+	assert.False(t, subject.rescan([]string{empty}), "file without comments removed")
+
+	c := write("sub/c.go", "c")
+	sub := filepath.Dir(c)
+	assert.True(t, subject.rescan([]string{sub}), "directory created")
+	assert.Equal(t, "c", contentOf(subject.parsed[c]))
+
+	broken := filepath.Join(sub, "broken.go")
+	assert.NoError(t, os.Symlink(filepath.Join(root, "missing.go"), broken))
+	assert.True(t, subject.rescan([]string{sub}), "file failed")
+	assert.Contains(t, subject.failures, broken)
+	assert.False(t, subject.rescan([]string{sub}), "same failure")
+
+	assert.NoError(t, os.RemoveAll(sub))
+	assert.True(t, subject.rescan([]string{sub}), "directory removed")
+	assert.Equal(t, []string{a}, keys(subject.parsed))
+	assert.Empty(t, subject.failures)
+}
+
+func keys(parsed map[string]parsedFile) []string {
+	out := []string{}
+	for file := range parsed {
+		out = append(out, file)
+	}
+	return out
+}
+
+type stubCodeOwners map[string][]string
+
+func (me stubCodeOwners) Owners(path string) []string {
+	return me[path]
+}
+
+func Test_reportOn_Owners(t *testing.T) {
+	root := t.TempDir()
+	path := func(name string) string {
+		return filepath.Join(root, name)
+	}
+	enforcer, err := gofixit.NewEnforcer(logrus.New(), contracts.EnforcerConfig{
+		Strict: true,
+		Now:    time.Now(),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	undated := func(line uint, owner string) contracts.ParsedComment {
+		return contracts.ParsedComment{
+			Prefix:     "TODO",
+			Owner:      owner,
+			LineNumber: line,
+		}
+	}
+	failed := errors.New("broken symlink")
+	subject := &scanned{
+		enforcer: enforcer,
+		codeOwners: stubCodeOwners{
+			"pay/p.c":   {"@org/pay"},
+			"pay/bad.c": {"@org/pay"},
+			"web/w.c":   {"@web"},
+			"web/bad.c": {"@web"},
+		},
+		root: root,
+		parsed: map[string]parsedFile{
+			path("pay/p.c"): {comments: []contracts.ParsedComment{undated(1, ""), undated(2, "")}},
+			path("web/w.c"): {comments: []contracts.ParsedComment{undated(1, ""), undated(2, "@pay")}},
+			path("doc/d.c"): {comments: []contracts.ParsedComment{undated(1, "")}},
+		},
+		failures: contracts.FilesErrors{
+			path("pay/bad.c"): failed,
+			path("web/bad.c"): failed,
+		},
+	}
+
+	tests := []struct {
+		name        string
+		owners      []string
+		wantResults []string
+		wantErrors  []string
+		wantFiles   []string
+		wantSummary contracts.Summary
+	}{
+		{
+			name:        "keeps everything without owners",
+			wantResults: []string{"doc/d.c:1", "pay/p.c:1", "pay/p.c:2", "web/w.c:1", "web/w.c:2"},
+			wantErrors:  []string{"pay/bad.c", "web/bad.c"},
+			wantFiles:   []string{"doc/d.c", "pay/p.c", "web/w.c"},
+			wantSummary: contracts.Summary{Files: 5, Comments: 5, Violations: 5, Errors: 2},
+		},
+		{
+			name:        "keeps what is owned or assigned",
+			owners:      []string{"@ORG/pay", "@pay"},
+			wantResults: []string{"pay/p.c:1", "pay/p.c:2", "web/w.c:2"},
+			wantErrors:  []string{"pay/bad.c"},
+			wantFiles:   []string{"pay/p.c", "web/w.c"},
+			wantSummary: contracts.Summary{Files: 3, Comments: 3, Violations: 3, Errors: 1},
+		},
+		{
+			name:        "keeps the files without comments which are owned",
+			owners:      []string{"@web"},
+			wantResults: []string{"web/w.c:1", "web/w.c:2"},
+			wantErrors:  []string{"web/bad.c"},
+			wantFiles:   []string{"web/w.c"},
+			wantSummary: contracts.Summary{Files: 2, Comments: 2, Violations: 2, Errors: 1},
+		},
+		{
+			name:        "keeps nothing for unknown owners",
+			owners:      []string{"@nobody"},
+			wantResults: []string{},
+			wantErrors:  []string{},
+			wantFiles:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject.ownerFilter = tt.owners
+			report, err := subject.report(false)
+			if !assert.NoError(t, err) {
+				return
+			}
+			relative := func(file string) string {
+				rel, err := filepath.Rel(root, file)
+				assert.NoError(t, err)
+				return filepath.ToSlash(rel)
+			}
+			results := []string{}
+			for _, result := range report.Results {
+				results = append(results, fmt.Sprintf("%s:%d", relative(result.Path), result.Comment.LineNumber))
+			}
+			failures := []string{}
+			for _, entry := range utils.SortedMap(report.Errors) {
+				failures = append(failures, relative(entry.Key))
+			}
+			files := []string{}
+			for _, file := range report.Files {
+				files = append(files, relative(file))
+			}
+			assert.Equal(t, tt.wantResults, results)
+			assert.Equal(t, tt.wantErrors, failures)
+			assert.Equal(t, tt.wantFiles, files)
+			assert.Equal(t, tt.wantSummary, report.Summary)
+		})
+	}
+}
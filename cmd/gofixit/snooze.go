@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	gofixit "github.com/LouisBrunner/gofixit/src"
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+)
+
+func snoozeFlags() {
+	addDefault([]string{"By"}, "", pflag.String, "how long to push the expiry date back, e.g. 2w or 30d")
+	addDefault([]string{"Reason"}, "", pflag.String, "why the comment is snoozed, recorded in the snooze log")
+	addDefault([]string{"Snooze", "Log"}, ".gofixit.snoozes.jsonl", pflag.String, "file where every snooze is recorded, one JSON object per line")
+	dryRunFlag()
+}
+
+type snoozeEntry struct {
+	Date    string  `json:"date"`
+	Path    string  `json:"path"`
+	Line    uint    `json:"line"`
+	Prefix  string  `json:"prefix"`
+	Content string  `json:"content"`
+	From    *string `json:"from"`
+	To      string  `json:"to"`
+	Snoozed *uint64 `json:"snoozed"`
+	Reason  string  `json:"reason"`
+}
+
+func parseTarget(target string) (string, uint, error) {
+	idx := strings.LastIndex(target, ":")
+	if idx <= 0 {
+		return "", 0, fmt.Errorf("invalid location %q, expected path:line", target)
+	}
+	line, err := strconv.ParseUint(target[idx+1:], 10, 0)
+	if err != nil || line == 0 {
+		return "", 0, fmt.Errorf("invalid line in %q, expected path:line", target)
+	}
+	return target[:idx], uint(line), nil
+}
+
+// storesMetadata tells if comments matched by the pattern can keep metadata, such as the snooze counter
+func storesMetadata(expiryPattern string) bool {
+	return strings.Contains(expiryPattern, ".Meta")
+}
+
+// snoozeCount returns how many times a comment was snoozed, 0 when it never was
+func snoozeCount(comment contracts.ParsedComment) (uint64, error) {
+	value, found := comment.Metadata[contracts.MetadataSnoozed]
+	if !found {
+		return 0, nil
+	}
+	count, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s count %q, fix or remove it", contracts.MetadataSnoozed, value)
+	}
+	return count, nil
+}
+
+func appendSnoozeLog(filename string, entry snoozeEntry) (err error) {
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	return json.NewEncoder(file).Encode(entry)
+}
+
+func runSnooze(log *logrus.Logger, params *args) (int, error) {
+	if len(params.targets) != 1 {
+		return exitInternal, errors.New("expected a single location, e.g. snooze path/to/file.go:42")
+	}
+	if params.snoozeBy == "" {
+		return exitInternal, errors.New("missing duration, use --by")
+	}
+	if params.snoozeReason == "" {
+		return exitInternal, errors.New("missing reason, use --reason")
+	}
+	filename, lineNumber, err := parseTarget(params.targets[0])
+	if err != nil {
+		return exitInternal, err
+	}
+	by, err := utils.ParseDuration(params.snoozeBy)
+	if err != nil {
+		return exitInternal, err
+	}
+	if by <= 0 {
+		return exitInternal, fmt.Errorf("invalid duration %q, --by must push the expiry date forward", params.snoozeBy)
+	}
+
+	parser, err := newParser(log, params)
+	if err != nil {
+		return exitInternal, err
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return exitInternal, err
+	}
+	comments, err := parser.Parse(string(content))
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while parsing %s (%w)", filename, err)
+	}
+	var comment *contracts.ParsedComment
+	for i := range comments {
+		if comments[i].LineNumber == lineNumber {
+			comment = &comments[i]
+		}
+	}
+	if comment == nil {
+		return exitInternal, fmt.Errorf("no comment found at %s:%d", filename, lineNumber)
+	}
+
+	// overdue comments are snoozed from today, otherwise the new date could already be in the past
+	now := time.Now()
+	from := now
+	if comment.Expiry != nil && comment.Expiry.After(now) {
+		from = *comment.Expiry
+	}
+	expiry := from.Add(by)
+
+	snoozed, err := snoozeCount(*comment)
+	if err != nil {
+		return exitInternal, fmt.Errorf("cannot snooze %s:%d (%w)", filename, lineNumber, err)
+	}
+	snoozed += 1
+	metadata := map[string]string{}
+	for key, value := range comment.Metadata {
+		metadata[key] = value
+	}
+	metadata[contracts.MetadataSnoozed] = strconv.FormatUint(snoozed, 10)
+	counted := storesMetadata(params.expiryPattern)
+	if !counted {
+		fmt.Fprintf(os.Stderr, "gofixit: ExpiryPattern has no {{.Meta}}, the number of snoozes is not recorded in the comment\n")
+	}
+
+	rewriter, err := gofixit.NewRewriter(log, contracts.RewriterConfig{
+		DryRun: params.dryRun,
+		Diff:   os.Stdout,
+	})
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while creating rewriter (%w)", err)
+	}
+	_, err = rewriter.Rewrite(filename, map[uint]contracts.LineRewriter{
+		lineNumber: func(line string) (string, error) {
+			return parser.Rewrite(line, expiry, metadata)
+		},
+	})
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while snoozing %s:%d (%w)", filename, lineNumber, err)
+	}
+	if params.dryRun {
+		return exitSuccess, nil
+	}
+
+	entry := snoozeEntry{
+		Date:    now.Format(time.RFC3339),
+		Path:    filename,
+		Line:    lineNumber,
+		Prefix:  comment.Prefix,
+		Content: strings.TrimSpace(comment.Content),
+		To:      expiry.Format(params.dateLayout),
+		Reason:  params.snoozeReason,
+	}
+	if counted {
+		entry.Snoozed = &snoozed
+	}
+	if comment.Expiry != nil {
+		previous := comment.Expiry.Format(params.dateLayout)
+		entry.From = &previous
+	}
+	if params.snoozeLog != "" {
+		err = appendSnoozeLog(params.snoozeLog, entry)
+		if err != nil {
+			return exitInternal, fmt.Errorf("failed while recording snooze (%w)", err)
+		}
+	}
+	if counted {
+		fmt.Fprintf(os.Stderr, "gofixit: snoozed %s:%d until %s (snoozed %d time(s))\n", filename, lineNumber, entry.To, snoozed)
+	} else {
+		fmt.Fprintf(os.Stderr, "gofixit: snoozed %s:%d until %s\n", filename, lineNumber, entry.To)
+	}
+	return exitSuccess, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		wantPath string
+		wantLine uint
+		wantErr  bool
+	}{
+		{
+			name:     "reads path and line",
+			target:   "src/main.go:42",
+			wantPath: "src/main.go",
+			wantLine: 42,
+		},
+		{
+			name:     "splits on the last colon",
+			target:   "C:/src/main.go:7",
+			wantPath: "C:/src/main.go",
+			wantLine: 7,
+		},
+		{
+			name:    "requires a line",
+			target:  "src/main.go",
+			wantErr: true,
+		},
+		{
+			name:    "requires a path",
+			target:  ":42",
+			wantErr: true,
+		},
+		{
+			name:    "rejects line 0",
+			target:  "src/main.go:0",
+			wantErr: true,
+		},
+		{
+			name:    "rejects invalid lines",
+			target:  "src/main.go:forty",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, line, err := parseTarget(tt.target)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.wantPath, path)
+				assert.Equal(t, tt.wantLine, line)
+			}
+		})
+	}
+}
+
+func Test_snoozeCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		want     uint64
+		wantErr  bool
+	}{
+		{
+			name: "starts at 0",
+		},
+		{
+			name:     "reads the counter",
+			metadata: map[string]string{contracts.MetadataSnoozed: "3"},
+			want:     3,
+		},
+		{
+			name:     "ignores other metadata",
+			metadata: map[string]string{"ticket": "ABC-1"},
+		},
+		{
+			name:     "rejects malformed counters",
+			metadata: map[string]string{contracts.MetadataSnoozed: "three"},
+			wantErr:  true,
+		},
+		{
+			name:     "rejects negative counters",
+			metadata: map[string]string{contracts.MetadataSnoozed: "-1"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := snoozeCount(contracts.ParsedComment{Metadata: tt.metadata})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_storesMetadata(t *testing.T) {
+	assert.True(t, storesMetadata(`\({{.Date}}{{.Meta}}\)`))
+	assert.False(t, storesMetadata(`\({{.Date}}\)`))
+}
+
+func Test_runSnooze_InvalidDuration(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "main.go")
+	content := []byte("// TODO[2024-01-01]: something\n")
+	if !assert.NoError(t, os.WriteFile(filename, content, 0o644)) {
+		return
+	}
+
+	for _, by := range []string{"0d", "-5000w", "soon"} {
+		t.Run(by, func(t *testing.T) {
+			code, err := runSnooze(logrus.New(), &args{
+				targets:      []string{filename + ":1"},
+				snoozeBy:     by,
+				snoozeReason: "waiting on upstream",
+			})
+			assert.Error(t, err)
+			assert.Equal(t, exitInternal, code)
+			got, err := os.ReadFile(filename)
+			if assert.NoError(t, err) {
+				assert.Equal(t, content, got)
+			}
+		})
+	}
+}
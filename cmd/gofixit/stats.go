@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	gofixit "github.com/LouisBrunner/gofixit/src"
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+	"github.com/hako/durafmt"
+	"github.com/sirupsen/logrus"
+)
+
+const statsDateLayout = "2006-01-02"
+
+type jsonMetrics struct {
+	Total             int     `json:"total"`
+	Dated             int     `json:"dated"`
+	Undated           int     `json:"undated"`
+	Invalid           int     `json:"invalid"`
+	Overdue           int     `json:"overdue"`
+	MedianOverdueDays int     `json:"median_overdue_days"`
+	NextExpiry        *string `json:"next_expiry"`
+}
+
+type jsonStats struct {
+	jsonMetrics
+	ByDirectory map[string]jsonMetrics `json:"by_directory"`
+	ByExtension map[string]jsonMetrics `json:"by_extension"`
+	ByPrefix    map[string]jsonMetrics `json:"by_prefix"`
+}
+
+func toJSONMetrics(metrics contracts.Metrics) jsonMetrics {
+	converted := jsonMetrics{
+		Total:             metrics.Total,
+		Dated:             metrics.Dated,
+		Undated:           metrics.Undated,
+		Invalid:           metrics.Invalid,
+		Overdue:           metrics.Overdue,
+		MedianOverdueDays: int(metrics.MedianOverdueAge / (24 * time.Hour)),
+	}
+	if metrics.NextExpiry != nil {
+		converted.NextExpiry = utils.Pointerize(metrics.NextExpiry.Format(statsDateLayout))
+	}
+	return converted
+}
+
+func toJSONGroups(groups map[string]contracts.Metrics) map[string]jsonMetrics {
+	converted := make(map[string]jsonMetrics, len(groups))
+	for key, metrics := range groups {
+		converted[key] = toJSONMetrics(metrics)
+	}
+	return converted
+}
+
+func printStatsJSON(out io.Writer, stats contracts.Stats) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jsonStats{
+		jsonMetrics: toJSONMetrics(stats.Metrics),
+		ByDirectory: toJSONGroups(stats.ByDirectory),
+		ByExtension: toJSONGroups(stats.ByExtension),
+		ByPrefix:    toJSONGroups(stats.ByPrefix),
+	})
+}
+
+func formatAge(age time.Duration) string {
+	if age == 0 {
+		return "-"
+	}
+	return durafmt.Parse(age).LimitFirstN(2).String()
+}
+
+func formatNextExpiry(now time.Time, expiry *time.Time) string {
+	if expiry == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s (in %s)", expiry.Format(statsDateLayout), durafmt.Parse(expiry.Sub(now)).LimitFirstN(2))
+}
+
+func printStatsText(out io.Writer, now time.Time, stats contracts.Stats) error {
+	table := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(table, "Comments:\t%d\n", stats.Total)
+	fmt.Fprintf(table, "Dated:\t%d\n", stats.Dated)
+	fmt.Fprintf(table, "Undated:\t%d\n", stats.Undated)
+	fmt.Fprintf(table, "Invalid:\t%d\n", stats.Invalid)
+	fmt.Fprintf(table, "Overdue:\t%d\n", stats.Overdue)
+	fmt.Fprintf(table, "Median overdue age:\t%s\n", formatAge(stats.MedianOverdueAge))
+	fmt.Fprintf(table, "Next expiry:\t%s\n", formatNextExpiry(now, stats.NextExpiry))
+	err := table.Flush()
+	if err != nil {
+		return err
+	}
+
+	for _, breakdown := range []struct {
+		title  string
+		groups map[string]contracts.Metrics
+	}{
+		{title: "DIRECTORY", groups: stats.ByDirectory},
+		{title: "EXTENSION", groups: stats.ByExtension},
+		{title: "PREFIX", groups: stats.ByPrefix},
+	} {
+		if len(breakdown.groups) == 0 {
+			continue
+		}
+		fmt.Fprintln(table)
+		fmt.Fprintf(table, "%s\tTOTAL\tDATED\tUNDATED\tINVALID\tOVERDUE\tMEDIAN OVERDUE AGE\tNEXT EXPIRY\n", breakdown.title)
+		for _, entry := range utils.SortedMap(breakdown.groups) {
+			name := entry.Key
+			if name == "" {
+				name = "(none)"
+			}
+			metrics := entry.Value
+			fmt.Fprintf(
+				table,
+				"%s\t%d\t%d\t%d\t%d\t%d\t%s\t%s\n",
+				name,
+				metrics.Total,
+				metrics.Dated,
+				metrics.Undated,
+				metrics.Invalid,
+				metrics.Overdue,
+				formatAge(metrics.MedianOverdueAge),
+				formatNextExpiry(now, metrics.NextExpiry),
+			)
+		}
+		err := table.Flush()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commonDirectory returns the deepest directory containing every file or directory, or "" when there is none
+func commonDirectory(files []string) string {
+	common := ""
+	for _, file := range files {
+		dir, err := filepath.Abs(file)
+		if err != nil {
+			return ""
+		}
+		if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+			dir = filepath.Dir(dir)
+		}
+		for common != "" && common != dir && !strings.HasPrefix(dir, common+string(filepath.Separator)) {
+			if common == filepath.Dir(common) {
+				break
+			}
+			common = filepath.Dir(common)
+		}
+		if common == "" {
+			common = dir
+		}
+	}
+	return common
+}
+
+func statsFormat() contracts.Format {
+	return contracts.FormatText
+}
+
+func runStats(log *logrus.Logger, params *args) (int, error) {
+	if params.format != contracts.FormatText && params.format != contracts.FormatJSON {
+		return exitInternal, fmt.Errorf("unsupported format %q for stats, must be text or json", params.format)
+	}
+
+	scanned, err := scan(log, params)
+	if err != nil {
+		return exitInternal, err
+	}
+	// implicit expiries are computed from the blame, which stats never prints
+	scanned.showBlame = true
+	report, err := scanned.report(true)
+	if err != nil {
+		return exitInternal, err
+	}
+
+	root := ""
+	if params.rev == "" {
+		root = commonDirectory(params.files)
+	}
+	collector, err := gofixit.NewStatsCollector(log, contracts.StatsConfig{
+		Now:            scanned.now,
+		Root:           root,
+		ImplicitExpiry: params.implicitExpiry,
+	})
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while creating stats collector (%w)", err)
+	}
+	stats := collector.Collect(report.Results)
+
+	if params.format == contracts.FormatJSON {
+		err = printStatsJSON(os.Stdout, stats)
+	} else {
+		err = printStatsText(os.Stdout, scanned.now, stats)
+	}
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while printing stats (%w)", err)
+	}
+	if len(scanned.failures) > 0 {
+		fmt.Fprintf(os.Stderr, "gofixit: %d file(s) could not be processed\n", len(scanned.failures))
+		return exitFileErrors, nil
+	}
+	return exitSuccess, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_commonDirectory(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"src/a", "src/b", "src2"} {
+		if !assert.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755)) {
+			return
+		}
+	}
+	file := filepath.Join(root, "src/a/main.go")
+	if !assert.NoError(t, os.WriteFile(file, []byte("package main\n"), 0o644)) {
+		return
+	}
+
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{
+			name: "returns nothing without files",
+		},
+		{
+			name:  "returns a directory itself",
+			files: []string{filepath.Join(root, "src")},
+			want:  filepath.Join(root, "src"),
+		},
+		{
+			name:  "returns the directory of a file",
+			files: []string{file},
+			want:  filepath.Join(root, "src/a"),
+		},
+		{
+			name:  "returns the deepest common directory",
+			files: []string{file, filepath.Join(root, "src/b")},
+			want:  filepath.Join(root, "src"),
+		},
+		{
+			name:  "does not stop at a shared prefix",
+			files: []string{filepath.Join(root, "src/a"), filepath.Join(root, "src2")},
+			want:  root,
+		},
+		{
+			name:  "goes up to the filesystem root",
+			files: []string{root, string(filepath.Separator)},
+			want:  string(filepath.Separator),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, commonDirectory(tt.files))
+		})
+	}
+}
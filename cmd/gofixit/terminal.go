@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+)
+
+// useColor checks whether the file is an interactive terminal which should get colors (see https://no-color.org)
+func useColor(file *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalFormat picks the format best suited to where the output goes
+func terminalFormat() contracts.Format {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		return contracts.FormatGitHub
+	} else if useColor(os.Stdout) {
+		return contracts.FormatPretty
+	}
+	return contracts.FormatText
+}
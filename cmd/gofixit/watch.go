@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	gofixit "github.com/LouisBrunner/gofixit/src"
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+)
+
+func watchFlags() {
+	checkFlags()
+	addDefault([]string{"Debounce"}, "300ms", pflag.String, "how long to wait after the last change before checking files again")
+}
+
+// dayCheckInterval is how often watch looks for a new day, comments expiring the previous day then become overdue
+const dayCheckInterval = time.Minute
+
+// newDay compares the wall clock dates, unlike timers which can run late when the computer was suspended
+func newDay(before, now time.Time) bool {
+	now = now.In(before.Location())
+	return now.Year() != before.Year() || now.YearDay() != before.YearDay()
+}
+
+// watcherCheck reports on the given files like check does (without the baseline warnings), paths being nil for
+// every file
+func watcherCheck(scanned *scanned, baseline contracts.Baseline, paths []string) (contracts.Report, error) {
+	report, err := scanned.reportOn(false, paths)
+	if err != nil {
+		return report, err
+	}
+	if baseline != nil {
+		ignoreBaselined(baseline, &report)
+	}
+	return report, nil
+}
+
+// watchReport prints the violations found in the given files (every file when nil) followed by a summary of the
+// whole project, it returns the exit code check would have
+func watchReport(log *logrus.Logger, scanned *scanned, params *args, baseline contracts.Baseline, paths []string) (int, error) {
+	total, err := watcherCheck(scanned, baseline, nil)
+	if err != nil {
+		return exitInternal, err
+	}
+	report := total
+	if paths != nil {
+		report, err = watcherCheck(scanned, baseline, paths)
+		if err != nil {
+			return exitInternal, err
+		}
+	}
+
+	reporter, err := gofixit.NewReporter(log, contracts.ReporterConfig{
+		Format:   params.format,
+		Output:   os.Stdout,
+		Template: params.template,
+		Now:      scanned.now,
+		Color:    useColor(os.Stdout),
+	})
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while creating reporter (%w)", err)
+	}
+	err = reporter.Report(report)
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while reporting results (%w)", err)
+	}
+	fmt.Fprintf(os.Stderr, "gofixit: %d violation(s) in %d file(s)", total.Summary.Violations, total.Summary.Files)
+	if len(total.Errors) > 0 {
+		fmt.Fprintf(os.Stderr, ", %d file(s) could not be processed", len(total.Errors))
+	}
+	fmt.Fprintf(os.Stderr, "\n")
+	return scanned.exitCode(total), nil
+}
+
+func runWatch(log *logrus.Logger, params *args) (int, error) {
+	if params.rev != "" {
+		return exitInternal, errors.New("watch only works on the working tree, --rev cannot be used")
+	}
+	scanned, err := scan(log, params)
+	if err != nil {
+		return exitInternal, err
+	}
+	baseline, err := loadBaseline(log, params, scanned.root)
+	if err != nil {
+		return exitInternal, err
+	}
+
+	watcher, err := gofixit.NewWatcher(log, contracts.WatcherConfig{
+		Paths:                params.files,
+		Recursive:            params.recursive,
+		FilesExcludePatterns: params.filesExcludePattern,
+		Debounce:             params.debounce,
+	})
+	if err != nil {
+		return exitInternal, fmt.Errorf("failed while watching files (%w)", err)
+	}
+	defer watcher.Close()
+
+	code, err := watchReport(log, scanned, params, baseline, nil)
+	if err != nil {
+		return code, err
+	}
+	fmt.Fprintf(os.Stderr, "gofixit: watching for changes, press Ctrl+C to stop\n")
+
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	defer signal.Stop(interrupted)
+	day := time.NewTicker(dayCheckInterval)
+	defer day.Stop()
+
+	for {
+		select {
+		case <-interrupted:
+			return code, nil
+		case err := <-watcher.Errors():
+			fmt.Fprintf(os.Stderr, "gofixit: %v\n", err)
+		case paths := <-watcher.Changes():
+			if !scanned.rescan(paths) {
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "\n[%s] %d path(s) changed\n", time.Now().Format("15:04:05"), len(paths))
+			code, err = watchReport(log, scanned, params, baseline, paths)
+			if err != nil {
+				return code, err
+			}
+		case now := <-day.C:
+			if !newDay(scanned.now, now) {
+				continue
+			}
+			err = scanned.reevaluate(log, now)
+			if err != nil {
+				return exitInternal, err
+			}
+			fmt.Fprintf(os.Stderr, "\n[%s] checking expiry dates again\n", scanned.now.Format("2006-01-02 15:04:05"))
+			code, err = watchReport(log, scanned, params, baseline, nil)
+			if err != nil {
+				return code, err
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newDay(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name   string
+		before time.Time
+		now    time.Time
+		want   bool
+	}{
+		{
+			name:   "same day",
+			before: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			now:    time.Date(2024, 6, 1, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			name:   "next day",
+			before: time.Date(2024, 6, 1, 23, 59, 0, 0, time.UTC),
+			now:    time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "several days later, e.g. after a suspend",
+			before: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+			now:    time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "same day of another year",
+			before: time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+			now:    time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "uses the time zone of the previous check",
+			before: time.Date(2024, 3, 30, 22, 0, 0, 0, paris),
+			now:    time.Date(2024, 3, 30, 23, 30, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "handles daylight saving time",
+			before: time.Date(2024, 3, 31, 0, 30, 0, 0, paris),
+			now:    time.Date(2024, 3, 31, 23, 30, 0, 0, paris),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newDay(tt.before, tt.now))
+		})
+	}
+}
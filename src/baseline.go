@@ -0,0 +1,11 @@
+package gofixit
+
+import (
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/internal/baseline"
+	"github.com/sirupsen/logrus"
+)
+
+func NewBaseline(logger *logrus.Logger, config contracts.BaselineConfig) (contracts.Baseline, error) {
+	return baseline.New(logger, config)
+}
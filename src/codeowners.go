@@ -0,0 +1,11 @@
+package gofixit
+
+import (
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/internal/codeowners"
+	"github.com/sirupsen/logrus"
+)
+
+func NewCodeOwners(logger *logrus.Logger, config contracts.CodeOwnersConfig) (contracts.CodeOwners, error) {
+	return codeowners.New(logger, config)
+}
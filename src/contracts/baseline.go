@@ -0,0 +1,42 @@
+package contracts
+
+// BaselineEntry identifies a known violation without relying on its line number, so that unrelated edits don't
+// invalidate it, the rule is part of it so that a comment breaking another rule is reported again
+type BaselineEntry struct {
+	Path   string `json:"path"`
+	Prefix string `json:"prefix"`
+	Rule   Rule   `json:"rule"`
+	Hash   string `json:"hash"`
+}
+
+// BaselineFile is the content of the baseline file
+type BaselineFile struct {
+	Version int             `json:"version"`
+	Entries []BaselineEntry `json:"entries"`
+}
+
+const BaselineVersion = 1
+
+type BaselineConfig struct {
+	Entries []BaselineEntry
+	// Root is the directory entry paths are relative to, e.g. the root of the repository (defaults to the current
+	// directory, which relative result paths are resolved against)
+	Root string
+}
+
+type BaselineMatch struct {
+	// New lists the results which are not part of the baseline
+	New []Result
+	// Baselined lists the results which are part of the baseline
+	Baselined []Result
+	// Fixed lists the entries of scanned files which didn't match any result and can be pruned
+	Fixed []BaselineEntry
+}
+
+// Baseline methods take the files which were scanned (e.g. Report.Files), entries of other files are left alone
+type Baseline interface {
+	// Record creates the entries matching the given results, keeping the existing entries of files which were not
+	// scanned
+	Record(results []Result, files []string) []BaselineEntry
+	Match(results []Result, files []string) BaselineMatch
+}
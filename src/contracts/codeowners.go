@@ -0,0 +1,17 @@
+package contracts
+
+import "io/fs"
+
+type CodeOwnersConfig struct {
+	// Directory is the root of the repository, where .github/CODEOWNERS, CODEOWNERS and docs/CODEOWNERS are looked for
+	// (in this order)
+	Directory string
+	// FS is the filesystem CODEOWNERS is read from, rooted at the root of the repository, e.g. a git revision (defaults
+	// to Directory on the OS filesystem)
+	FS fs.FS
+}
+
+type CodeOwners interface {
+	// Owners returns the owners of a path relative to Directory according to the last matching rule, if any
+	Owners(path string) []string
+}
@@ -5,8 +5,60 @@ import "time"
 type EnforcerConfig struct {
 	Now    time.Time
 	Strict bool
+	// WarningPeriod is how long before their expiry comments are given StatusWarning
+	WarningPeriod time.Duration
+	// MaxSnoozes is how many times a comment can be snoozed (through its `snoozed` metadata), 0 means no limit
+	MaxSnoozes uint
+	// ImplicitExpiry gives undated comments an expiry date this long after their line was committed (see
+	// ParsedComment.Blame) instead of failing them in strict mode, 0 disables it
+	ImplicitExpiry time.Duration
+	// ReportUnusedSuppressions reports suppressions which don't cover any violation
+	ReportUnusedSuppressions bool
+}
+
+type Status string
+
+const (
+	StatusOK      Status = "ok"
+	StatusWarning Status = "warning"
+	StatusOverdue Status = "overdue"
+	StatusUndated Status = "undated"
+	StatusInvalid Status = "invalid"
+)
+
+type Rule string
+
+const (
+	RuleOverdue       Rule = "overdue"
+	RuleMissingExpiry Rule = "missing-expiry"
+	RuleInvalidDate   Rule = "invalid-date"
+	RuleSnoozeLimit   Rule = "snooze-limit"
+	// RuleInvalidSuppression and RuleUnusedSuppression are about suppressions rather than comments
+	RuleInvalidSuppression Rule = "invalid-suppression"
+	RuleUnusedSuppression  Rule = "unused-suppression"
+)
+
+type Severity string
+
+const (
+	SeverityError Severity = "error"
+)
+
+// Violation is the error returned by Enforcer.Check when a comment breaks one of the rules
+type Violation struct {
+	Rule     Rule
+	Severity Severity
+	Message  string
+}
+
+func (me *Violation) Error() string {
+	return me.Message
 }
 
 type Enforcer interface {
 	Check(comment ParsedComment) error
+	Status(comment ParsedComment) Status
+	// Suppress clears the violations of results covered by the suppressions found in the same file, it returns the
+	// updated results and violations for the suppressions which are invalid (or unused with ReportUnusedSuppressions)
+	Suppress(path string, results []Result, suppressions []Suppression) ([]Result, []Result)
 }
@@ -1,13 +1,58 @@
 package contracts
 
-type FileProcessor[T any] func(filepath string) (T, error)
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+type FileProcessor[T any] func(filepath string, content io.Reader) (T, error)
 
 type FilesProcessor[T any] interface {
 	ProcessFiles(files []string) (map[string]T, error)
 }
 
+var ErrBrokenSymlink = errors.New("broken symlink")
+
+var ErrArchiveMemberTooLarge = errors.New("archive member too large")
+
+// FilesErrors is returned by ProcessFiles in KeepGoing mode (alongside the results of every other file) when
+// some files could not be processed
+type FilesErrors map[string]error
+
+func (me FilesErrors) Error() string {
+	names := make([]string, 0, len(me))
+	for name := range me {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	messages := make([]string, 0, len(names))
+	for _, name := range names {
+		messages = append(messages, me[name].Error())
+	}
+	return fmt.Sprintf("failed to process %d file(s): %s", len(me), strings.Join(messages, ", "))
+}
+
 type FilesProcessorConfig[T any] struct {
 	Processor            FileProcessor[T]
 	Recursive            bool
 	FilesExcludePatterns []string
+	// ScanArchives makes zip, jar, tar and tar.gz files be processed member by member, each being reported
+	// as `archive!/member`
+	ScanArchives bool
+	// MaxArchiveMemberSize is the size in bytes above which archive members are not processed and reported as
+	// errors instead (defaults to 64 MiB)
+	MaxArchiveMemberSize int64
+	// SkipSymlinks makes symlinks found while walking directories be ignored, otherwise they are processed (directory
+	// loops are detected and skipped), symlinks given explicitly are always followed
+	SkipSymlinks bool
+	// KeepGoing records per-file errors instead of stopping at the first one
+	KeepGoing bool
+	// FS is the filesystem files are read from, paths are then relative to its root (defaults to the OS
+	// filesystem through os.DirFS, where relative and absolute paths are both supported)
+	FS fs.FS
 }
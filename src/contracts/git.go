@@ -0,0 +1,29 @@
+package contracts
+
+import "time"
+
+// GitFSConfig configures a filesystem reading files from a git revision, it also implements io.Closer to stop the
+// git process reading files (started again when needed)
+type GitFSConfig struct {
+	Directory string
+	Revision  string
+}
+
+// Blame describes the commit which last changed a line
+type Blame struct {
+	Commit      string
+	Author      string
+	AuthorEmail string
+	Date        time.Time
+}
+
+type GitBlamerConfig struct {
+	Directory string
+	// Revision to blame files at, the working tree is used when empty
+	Revision string
+}
+
+type GitBlamer interface {
+	// Blame returns the blame of every line of the file by line number, lines which are not committed yet are missing
+	Blame(filepath string) (map[uint]Blame, error)
+}
@@ -5,10 +5,51 @@ import "time"
 type ParsedComment struct {
 	CommentPrefix string
 	Prefix        string
+	Owner         string
 	Content       string
 	Expiry        *time.Time
-	LineNumber    uint
-	OriginalLine  string
+	// InvalidExpiry contains the matched date when it could not be parsed (Expiry is then nil)
+	InvalidExpiry string
+	// Metadata holds the `key=value` pairs following the expiry when ExpiryPattern uses {{.Meta}}
+	Metadata   map[string]string
+	LineNumber uint
+	Column     uint
+	// OriginalLine only holds the matched comment, which starts at Column in SourceLine
+	OriginalLine string
+	SourceLine   string
+	// Blame is the commit which last changed the line, when known (it isn't set by the parser)
+	Blame *Blame
+}
+
+// MetadataSnoozed counts how many times a comment was snoozed
+const MetadataSnoozed = "snoozed"
+
+// SuppressionDirective starts every suppression, e.g. `gofixit:ignore`
+const SuppressionDirective = "gofixit:"
+
+type SuppressionKind string
+
+const (
+	// SuppressionIgnore applies to the comment on the same line, or on the next line when there is none
+	SuppressionIgnore     SuppressionKind = "ignore"
+	SuppressionIgnoreFile SuppressionKind = "ignore-file"
+	// SuppressionDisable applies until the next SuppressionEnable with the same rule, or the end of the file
+	SuppressionDisable SuppressionKind = "disable"
+	SuppressionEnable  SuppressionKind = "enable"
+)
+
+// Suppression is a `gofixit:<kind> [rule] -- <reason>` directive found in a comment
+type Suppression struct {
+	Kind SuppressionKind
+	// Rule restricts the suppression to a single rule, every rule is suppressed when empty
+	Rule   Rule
+	Reason string
+	// Invalid explains why the directive cannot be used (e.g. a missing reason), it is then ignored
+	Invalid      string
+	LineNumber   uint
+	Column       uint
+	OriginalLine string
+	SourceLine   string
 }
 
 type ParsingConfig struct {
@@ -21,4 +62,8 @@ type ParsingConfig struct {
 
 type Parser interface {
 	Parse(fileContent string) ([]ParsedComment, error)
+	ParseSuppressions(fileContent string) ([]Suppression, error)
+	// Rewrite replaces the prefix, owner, expiry and metadata of the comment found in line to use the given expiry
+	// and metadata (which is dropped when ExpiryPattern doesn't use {{.Meta}})
+	Rewrite(line string, expiry time.Time, metadata map[string]string) (string, error)
 }
@@ -0,0 +1,64 @@
+package contracts
+
+import (
+	"io"
+	"time"
+)
+
+type Result struct {
+	Path      string
+	Comment   ParsedComment
+	Violation *Violation
+	Status    Status
+	// CodeOwners lists the owners of Path according to CODEOWNERS
+	CodeOwners []string
+}
+
+type Summary struct {
+	Files      int
+	Comments   int
+	Violations int
+	Errors     int
+}
+
+type Report struct {
+	// Files lists every file which was processed successfully
+	Files   []string
+	Results []Result
+	Errors  FilesErrors
+	Summary Summary
+}
+
+type Format string
+
+const (
+	FormatText       Format = "text"
+	FormatPretty     Format = "pretty"
+	FormatJSON       Format = "json"
+	FormatNDJSON     Format = "ndjson"
+	FormatSARIF      Format = "sarif"
+	FormatJUnit      Format = "junit"
+	FormatCheckstyle Format = "checkstyle"
+	FormatGitHub     Format = "github"
+	FormatGitLab     Format = "gitlab"
+	FormatTemplate   Format = "template"
+	FormatHTML       Format = "html"
+	FormatMarkdown   Format = "markdown"
+	FormatCSV        Format = "csv"
+	FormatICal       Format = "ical"
+	FormatTable      Format = "table"
+)
+
+type ReporterConfig struct {
+	Format Format
+	Output io.Writer
+	// Template is the Go template used by FormatTemplate, executed once per result
+	Template string
+	// Now is used to display relative times
+	Now   time.Time
+	Color bool
+}
+
+type Reporter interface {
+	Report(report Report) error
+}
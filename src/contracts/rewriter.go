@@ -0,0 +1,18 @@
+package contracts
+
+import "io"
+
+// LineRewriter returns the new content of a line, given without its line ending
+type LineRewriter func(line string) (string, error)
+
+type RewriterConfig struct {
+	// DryRun writes a unified diff of the changes to Diff instead of modifying files
+	DryRun bool
+	Diff   io.Writer
+}
+
+type Rewriter interface {
+	// Rewrite applies each LineRewriter to its line (starting at 1) and returns the sorted numbers of the lines which
+	// changed, line endings, encoding and permissions are kept as they are and the file is replaced atomically
+	Rewrite(filepath string, lines map[uint]LineRewriter) ([]uint, error)
+}
@@ -0,0 +1,37 @@
+package contracts
+
+import "time"
+
+type StatsConfig struct {
+	Now time.Time
+	// Root is the directory paths are made relative to before being grouped by directory (defaults to the current
+	// directory, which relative paths are resolved against)
+	Root string
+	// ImplicitExpiry dates undated comments which are overdue because of EnforcerConfig.ImplicitExpiry, to compute
+	// their overdue age
+	ImplicitExpiry time.Duration
+}
+
+type Metrics struct {
+	Total   int
+	Dated   int
+	Undated int
+	Invalid int
+	Overdue int
+	// MedianOverdueAge is the median time elapsed since the expiry of overdue comments
+	MedianOverdueAge time.Duration
+	// NextExpiry is the closest expiry date which hasn't passed yet, if any
+	NextExpiry *time.Time
+}
+
+type Stats struct {
+	Metrics
+	ByDirectory map[string]Metrics
+	ByExtension map[string]Metrics
+	ByPrefix    map[string]Metrics
+}
+
+type StatsCollector interface {
+	// Collect counts comments as overdue according to their Status (see Enforcer.Status)
+	Collect(results []Result) Stats
+}
@@ -0,0 +1,21 @@
+package contracts
+
+import "time"
+
+type WatcherConfig struct {
+	// Paths are the files and directories to watch, directories are watched with all their subdirectories when
+	// Recursive is set
+	Paths     []string
+	Recursive bool
+	// FilesExcludePatterns skip the matching files and directories found under Paths, like FilesProcessorConfig
+	FilesExcludePatterns []string
+	// Debounce is how long to wait after the last change before sending a batch of changes
+	Debounce time.Duration
+}
+
+type Watcher interface {
+	// Changes receives the sorted paths which were created, written, removed or renamed since the last batch
+	Changes() <-chan []string
+	Errors() <-chan error
+	Close() error
+}
@@ -0,0 +1,17 @@
+package gofixit
+
+import (
+	"io/fs"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/internal/git"
+	"github.com/sirupsen/logrus"
+)
+
+func NewGitFS(logger *logrus.Logger, config contracts.GitFSConfig) (fs.FS, error) {
+	return git.NewFS(logger, config)
+}
+
+func NewGitBlamer(logger *logrus.Logger, config contracts.GitBlamerConfig) (contracts.GitBlamer, error) {
+	return git.NewBlamer(logger, config)
+}
@@ -0,0 +1,129 @@
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+)
+
+type baseline struct {
+	contracts.BaselineConfig
+	logger *logrus.Logger
+}
+
+func New(logger *logrus.Logger, config contracts.BaselineConfig) (contracts.Baseline, error) {
+	if config.Root == "" {
+		config.Root = "."
+	}
+	root, err := filepath.Abs(config.Root)
+	if err != nil {
+		return nil, err
+	}
+	config.Root = root
+	return &baseline{
+		BaselineConfig: config,
+		logger:         logger,
+	}, nil
+}
+
+// normalize makes paths relative to Root, so the same file is always recorded the same way, archive members keep
+// their `!/member` suffix
+func (me *baseline) normalize(name string) string {
+	name, member, inArchive := strings.Cut(name, "!/")
+	if absolute, err := filepath.Abs(name); err == nil {
+		if relative, err := filepath.Rel(me.Root, absolute); err == nil {
+			name = relative
+		}
+	}
+	name = filepath.ToSlash(filepath.Clean(name))
+	if inArchive {
+		name += "!/" + member
+	}
+	return name
+}
+
+// entryFor ignores the expiry date and the line number of the comment, only its text and the rule it breaks
+// identify it
+func (me *baseline) entryFor(result contracts.Result) contracts.BaselineEntry {
+	hash := sha256.Sum256([]byte(strings.TrimSpace(result.Comment.Content)))
+	entry := contracts.BaselineEntry{
+		Path:   me.normalize(result.Path),
+		Prefix: result.Comment.Prefix,
+		Hash:   hex.EncodeToString(hash[:]),
+	}
+	if result.Violation != nil {
+		entry.Rule = result.Violation.Rule
+	}
+	return entry
+}
+
+func (me *baseline) scanned(files []string) map[string]bool {
+	scanned := make(map[string]bool, len(files))
+	for _, file := range files {
+		scanned[me.normalize(file)] = true
+	}
+	return scanned
+}
+
+func (me *baseline) Record(results []contracts.Result, files []string) []contracts.BaselineEntry {
+	scanned := me.scanned(files)
+	entries := make([]contracts.BaselineEntry, 0, len(results))
+	for _, entry := range me.Entries {
+		if !scanned[entry.Path] {
+			entries = append(entries, entry)
+		}
+	}
+	for _, result := range results {
+		entries = append(entries, me.entryFor(result))
+	}
+	// keep the file stable when comments move around
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Path != entries[j].Path {
+			return entries[i].Path < entries[j].Path
+		}
+		if entries[i].Prefix != entries[j].Prefix {
+			return entries[i].Prefix < entries[j].Prefix
+		}
+		if entries[i].Rule != entries[j].Rule {
+			return entries[i].Rule < entries[j].Rule
+		}
+		return entries[i].Hash < entries[j].Hash
+	})
+	return entries
+}
+
+func (me *baseline) Match(results []contracts.Result, files []string) contracts.BaselineMatch {
+	scanned := me.scanned(files)
+	// identical comments in the same file are each matched by their own entry
+	remaining := map[contracts.BaselineEntry]int{}
+	for _, entry := range me.Entries {
+		if scanned[entry.Path] {
+			remaining[entry] += 1
+		}
+	}
+
+	match := contracts.BaselineMatch{}
+	for _, result := range results {
+		entry := me.entryFor(result)
+		if remaining[entry] > 0 {
+			remaining[entry] -= 1
+			match.Baselined = append(match.Baselined, result)
+			continue
+		}
+		match.New = append(match.New, result)
+	}
+
+	for _, entry := range me.Entries {
+		if remaining[entry] > 0 {
+			me.logger.Debugf("baseline entry %s %s %s in %s was fixed", entry.Prefix, entry.Rule, entry.Hash, entry.Path)
+			remaining[entry] -= 1
+			match.Fixed = append(match.Fixed, entry)
+		}
+	}
+	return match
+}
@@ -0,0 +1,228 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func result(path string, line uint, prefix, content string) contracts.Result {
+	return contracts.Result{
+		Path: path,
+		Comment: contracts.ParsedComment{
+			LineNumber: line,
+			Prefix:     prefix,
+			Content:    content,
+		},
+		Violation: &contracts.Violation{
+			Rule: contracts.RuleOverdue,
+		},
+	}
+}
+
+func breaking(rule contracts.Rule, result contracts.Result) contracts.Result {
+	result.Violation = &contracts.Violation{
+		Rule: rule,
+	}
+	return result
+}
+
+func Test_Record(t *testing.T) {
+	subject, err := New(logrus.New(), contracts.BaselineConfig{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	got := subject.Record([]contracts.Result{
+		result("src/main.c", 12, "TODO", ": implement later"),
+		result("./lib/util.go", 3, "FIXME", " broken "),
+	}, []string{"src/main.c", "./lib/util.go"})
+	assert.Equal(t, []contracts.BaselineEntry{
+		{Path: "lib/util.go", Prefix: "FIXME", Rule: contracts.RuleOverdue, Hash: "f526795c95399cea27c055c842c3d6ab018ed0fa4f66f701c28ab22dec28237b"},
+		{Path: "src/main.c", Prefix: "TODO", Rule: contracts.RuleOverdue, Hash: "55b83fa590bb68324af784d7fa1268447c914091ab8b0c8c0a70f6d51e770291"},
+	}, got)
+}
+
+func Test_Record_KeepsUnscannedFiles(t *testing.T) {
+	existing := []contracts.BaselineEntry{
+		{Path: "lib/util.go", Prefix: "FIXME", Rule: contracts.RuleOverdue, Hash: "f526795c95399cea27c055c842c3d6ab018ed0fa4f66f701c28ab22dec28237b"},
+		{Path: "src/main.c", Prefix: "TODO", Rule: contracts.RuleOverdue, Hash: "55b83fa590bb68324af784d7fa1268447c914091ab8b0c8c0a70f6d51e770291"},
+	}
+	subject, err := New(logrus.New(), contracts.BaselineConfig{
+		Entries: existing,
+		Root:    "/repo",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	got := subject.Record([]contracts.Result{
+		result("/repo/src/main.c", 4, "TODO", ": other"),
+	}, []string{"/repo/src/main.c"})
+	assert.Equal(t, []contracts.BaselineEntry{
+		existing[0],
+		{Path: "src/main.c", Prefix: "TODO", Rule: contracts.RuleOverdue, Hash: "028341fd63aa440621ddfd0bd54b6d15b1b0bf4fb0ab58efdda8be889cf5b569"},
+	}, got)
+}
+
+func Test_Match(t *testing.T) {
+	recorded := []contracts.Result{
+		result("src/main.c", 4, "TODO", ": implement later"),
+		result("src/main.c", 12, "FIXME", ": broken"),
+		result("src/main.c", 20, "FIXME", ": broken"),
+		result("lib/util.go", 3, "TODO", ": remove"),
+	}
+	subject, err := New(logrus.New(), contracts.BaselineConfig{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	entries := subject.Record(recorded, nil)
+	cwd, err := os.Getwd()
+	if !assert.NoError(t, err) {
+		return
+	}
+	everything := []string{"src/main.c", "lib/util.go", "lib/other.go"}
+
+	tests := []struct {
+		name    string
+		entries []contracts.BaselineEntry
+		results []contracts.Result
+		files   []string
+		want    contracts.BaselineMatch
+	}{
+		{
+			name:    "works without baseline",
+			results: recorded[:1],
+			want: contracts.BaselineMatch{
+				New: recorded[:1],
+			},
+		},
+		{
+			name:    "ignores line numbers",
+			entries: entries,
+			results: []contracts.Result{
+				result("src/main.c", 40, "TODO", ": implement later"),
+				result("src/main.c", 1, "FIXME", ": broken"),
+				result("src/main.c", 2, "FIXME", ": broken"),
+				result("lib/util.go", 30, "TODO", ": remove"),
+			},
+			want: contracts.BaselineMatch{
+				Baselined: []contracts.Result{
+					result("src/main.c", 40, "TODO", ": implement later"),
+					result("src/main.c", 1, "FIXME", ": broken"),
+					result("src/main.c", 2, "FIXME", ": broken"),
+					result("lib/util.go", 30, "TODO", ": remove"),
+				},
+			},
+		},
+		{
+			name:    "reports new and fixed entries",
+			entries: entries,
+			results: []contracts.Result{
+				result("src/main.c", 4, "TODO", ": implement later"),
+				result("src/main.c", 12, "FIXME", ": broken"),
+				result("src/main.c", 20, "FIXME", ": broken"),
+				result("src/main.c", 30, "FIXME", ": broken"),
+				result("lib/util.go", 3, "TODO", ": remove soon"),
+				result("lib/other.go", 3, "TODO", ": remove"),
+			},
+			want: contracts.BaselineMatch{
+				New: []contracts.Result{
+					result("src/main.c", 30, "FIXME", ": broken"),
+					result("lib/util.go", 3, "TODO", ": remove soon"),
+					result("lib/other.go", 3, "TODO", ": remove"),
+				},
+				Baselined: []contracts.Result{
+					result("src/main.c", 4, "TODO", ": implement later"),
+					result("src/main.c", 12, "FIXME", ": broken"),
+					result("src/main.c", 20, "FIXME", ": broken"),
+				},
+				Fixed: []contracts.BaselineEntry{
+					entries[0],
+				},
+			},
+		},
+		{
+			name:    "reports comments breaking another rule",
+			entries: entries,
+			results: []contracts.Result{
+				breaking(contracts.RuleSnoozeLimit, result("lib/util.go", 3, "TODO", ": remove")),
+			},
+			files: []string{"lib/util.go"},
+			want: contracts.BaselineMatch{
+				New: []contracts.Result{
+					breaking(contracts.RuleSnoozeLimit, result("lib/util.go", 3, "TODO", ": remove")),
+				},
+				Fixed: []contracts.BaselineEntry{
+					entries[0],
+				},
+			},
+		},
+		{
+			name:    "matches each entry once",
+			entries: entries,
+			results: []contracts.Result{
+				result("src/main.c", 12, "FIXME", ": broken"),
+			},
+			want: contracts.BaselineMatch{
+				Baselined: []contracts.Result{
+					result("src/main.c", 12, "FIXME", ": broken"),
+				},
+				Fixed: []contracts.BaselineEntry{
+					entries[0],
+					entries[2],
+					entries[3],
+				},
+			},
+		},
+		{
+			name:    "ignores entries of files which were not scanned",
+			entries: entries,
+			results: []contracts.Result{
+				result("src/main.c", 12, "FIXME", ": broken"),
+			},
+			files: []string{"src/main.c"},
+			want: contracts.BaselineMatch{
+				Baselined: []contracts.Result{
+					result("src/main.c", 12, "FIXME", ": broken"),
+				},
+				Fixed: []contracts.BaselineEntry{
+					entries[2],
+					entries[3],
+				},
+			},
+		},
+		{
+			name:    "matches absolute paths",
+			entries: entries,
+			results: []contracts.Result{
+				result(filepath.Join(cwd, "lib/util.go"), 3, "TODO", ": remove"),
+			},
+			files: []string{filepath.Join(cwd, "lib/util.go")},
+			want: contracts.BaselineMatch{
+				Baselined: []contracts.Result{
+					result(filepath.Join(cwd, "lib/util.go"), 3, "TODO", ": remove"),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject, err := New(logrus.New(), contracts.BaselineConfig{
+				Entries: tt.entries,
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			files := tt.files
+			if files == nil {
+				files = everything
+			}
+			assert.Equal(t, tt.want, subject.Match(tt.results, files))
+		})
+	}
+}
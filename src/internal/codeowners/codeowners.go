@@ -0,0 +1,149 @@
+package codeowners
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+)
+
+// locations are checked in the same order as GitHub
+var locations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+type rule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+type codeOwners struct {
+	contracts.CodeOwnersConfig
+	logger *logrus.Logger
+	rules  []rule
+}
+
+func New(logger *logrus.Logger, config contracts.CodeOwnersConfig) (contracts.CodeOwners, error) {
+	me := &codeOwners{
+		CodeOwnersConfig: config,
+		logger:           logger,
+	}
+	fsys := config.FS
+	if fsys == nil {
+		fsys = os.DirFS(config.Directory)
+	}
+	for _, location := range locations {
+		filename := filepath.Join(config.Directory, filepath.FromSlash(location))
+		file, err := fsys.Open(location)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		defer file.Close()
+
+		logger.Infof("using code owners from %s", filename)
+		me.rules, err = parse(logger, file)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", filename, err)
+		}
+		break
+	}
+	return me, nil
+}
+
+func parse(logger *logrus.Logger, file io.Reader) ([]rule, error) {
+	rules := []rule{}
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		owners := []string{}
+		for _, owner := range fields[1:] {
+			// owners can be followed by a comment
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			owners = append(owners, owner)
+		}
+		// like GitHub, skip the syntax it doesn't support instead of failing
+		if strings.HasPrefix(fields[0], "!") || strings.ContainsAny(fields[0], "[]") {
+			logger.Warnf("unsupported CODEOWNERS pattern %q on line %d", fields[0], number)
+			continue
+		}
+		pattern, err := compile(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		rules = append(rules, rule{pattern: pattern, owners: owners})
+	}
+	return rules, scanner.Err()
+}
+
+// compile converts a CODEOWNERS pattern, which follows most gitignore rules, into a regex matching slash-separated
+// paths relative to the root of the repository
+func compile(pattern string) (*regexp.Regexp, error) {
+	// patterns with a slash (other than a trailing one) are relative to the root, others match at any depth
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	builder := &strings.Builder{}
+	builder.WriteString("^")
+	if !anchored {
+		builder.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			builder.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			builder.WriteString(".*")
+			i += 1
+		case pattern[i] == '*':
+			builder.WriteString("[^/]*")
+		case pattern[i] == '?':
+			builder.WriteString("[^/]")
+		default:
+			builder.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	switch {
+	case directory:
+		builder.WriteString("/.*")
+	case strings.HasSuffix(pattern, "/*") && !strings.HasSuffix(pattern, "/**"):
+		// unlike gitignore, `docs/*` doesn't match the content of subdirectories
+	default:
+		builder.WriteString("(?:/.*)?")
+	}
+	builder.WriteString("$")
+	return regexp.Compile(builder.String())
+}
+
+func (me *codeOwners) Owners(name string) []string {
+	name = path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	for i := len(me.rules) - 1; i >= 0; i-- {
+		if me.rules[i].pattern.MatchString(name) {
+			me.logger.Debugf("%s owned by %v", name, me.rules[i].owners)
+			return me.rules[i].owners
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Owners(t *testing.T) {
+	codeowners := `# default owners
+*       @org/everyone
+
+*.js    @org/frontend # inline comment
+/build/logs/ @org/ops
+apps/   @org/apps
+docs/*  docs@example.com
+**/payments @org/team-payments
+/scripts/**/deploy.sh @org/ops @alice
+/vendor/
+`
+
+	tests := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{
+			name: "works with a default rule",
+			path: "README.md",
+			want: []string{"@org/everyone"},
+		},
+		{
+			name: "works with extensions at any depth",
+			path: "src/web/index.js",
+			want: []string{"@org/frontend"},
+		},
+		{
+			name: "works with anchored directories",
+			path: "build/logs/2022/today.log",
+			want: []string{"@org/ops"},
+		},
+		{
+			name: "ignores anchored directories elsewhere",
+			path: "src/build/logs/today.log",
+			want: []string{"@org/everyone"},
+		},
+		{
+			name: "works with directories at any depth",
+			path: "src/apps/main.go",
+			want: []string{"@org/apps"},
+		},
+		{
+			name: "works with direct children",
+			path: "docs/index.md",
+			want: []string{"docs@example.com"},
+		},
+		{
+			name: "ignores nested children",
+			path: "docs/guides/index.md",
+			want: []string{"@org/everyone"},
+		},
+		{
+			name: "works with double stars",
+			path: "src/billing/payments/api.go",
+			want: []string{"@org/team-payments"},
+		},
+		{
+			name: "works with double stars in the middle",
+			path: "scripts/a/b/deploy.sh",
+			want: []string{"@org/ops", "@alice"},
+		},
+		{
+			name: "works with unowned paths",
+			path: "vendor/lib/lib.go",
+			want: []string{},
+		},
+		{
+			name: "cleans paths",
+			path: "./src/../apps/main.go",
+			want: []string{"@org/apps"},
+		},
+	}
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "CODEOWNERS"), []byte(codeowners), 0o644)
+	if err != nil {
+		t.Fatalf("could not write CODEOWNERS: %v", err)
+	}
+	me, err := New(logrus.New(), contracts.CodeOwnersConfig{Directory: dir})
+	if err != nil {
+		t.Fatalf("failed to create code owners: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, me.Owners(tt.path))
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "works without CODEOWNERS",
+		},
+		{
+			name: "prefers .github",
+			files: map[string]string{
+				"CODEOWNERS":         "* @root",
+				".github/CODEOWNERS": "* @github",
+				"docs/CODEOWNERS":    "* @docs",
+			},
+			want: []string{"@github"},
+		},
+		{
+			name: "prefers the root over docs",
+			files: map[string]string{
+				"CODEOWNERS":      "* @root",
+				"docs/CODEOWNERS": "* @docs",
+			},
+			want: []string{"@root"},
+		},
+		{
+			name: "works with docs",
+			files: map[string]string{
+				"docs/CODEOWNERS": "* @docs",
+			},
+			want: []string{"@docs"},
+		},
+		{
+			name: "skips unsupported patterns",
+			files: map[string]string{
+				"CODEOWNERS": "* @root\n!main.go @negated\n[mM]ain.go @range",
+			},
+			want: []string{"@root"},
+		},
+		{
+			name: "fails with unreadable files",
+			files: map[string]string{
+				"CODEOWNERS/file": "",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755)
+				if err == nil {
+					err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+				}
+				if err != nil {
+					t.Fatalf("could not write %s: %v", name, err)
+				}
+			}
+
+			me, err := New(logrus.New(), contracts.CodeOwnersConfig{Directory: dir})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, tt.want, me.Owners("main.go"))
+		})
+	}
+}
+
+func Test_New_FS(t *testing.T) {
+	me, err := New(logrus.New(), contracts.CodeOwnersConfig{
+		Directory: t.TempDir(),
+		FS: fstest.MapFS{
+			".github/CODEOWNERS": &fstest.MapFile{Data: []byte("* @revision\n")},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"@revision"}, me.Owners("main.go"))
+}
@@ -2,12 +2,16 @@ package enforcer
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/LouisBrunner/gofixit/src/contracts"
 	"github.com/hako/durafmt"
 	"github.com/sirupsen/logrus"
 )
 
+const blameDateLayout = "2006-01-02"
+
 type enforcer struct {
 	contracts.EnforcerConfig
 	logger *logrus.Logger
@@ -21,16 +25,94 @@ func New(logger *logrus.Logger, config contracts.EnforcerConfig) (contracts.Enfo
 }
 
 func (me *enforcer) Check(comment contracts.ParsedComment) error {
+	if comment.InvalidExpiry != "" {
+		return &contracts.Violation{
+			Rule:     contracts.RuleInvalidDate,
+			Severity: contracts.SeverityError,
+			Message:  fmt.Sprintf("%s has an invalid expiry date %q", comment.Prefix, comment.InvalidExpiry),
+		}
+	}
+
+	if me.MaxSnoozes > 0 {
+		if value, found := comment.Metadata[contracts.MetadataSnoozed]; found {
+			snoozed, err := strconv.ParseUint(value, 10, 0)
+			if err != nil {
+				me.logger.Warnf("invalid %s count %q: %v", contracts.MetadataSnoozed, value, err)
+			} else if uint(snoozed) > me.MaxSnoozes {
+				return &contracts.Violation{
+					Rule:     contracts.RuleSnoozeLimit,
+					Severity: contracts.SeverityError,
+					Message:  fmt.Sprintf("%s snoozed %d times, more than the allowed %d", comment.Prefix, snoozed, me.MaxSnoozes),
+				}
+			}
+		}
+	}
+
 	if comment.Expiry == nil {
+		if me.ImplicitExpiry > 0 {
+			if me.implicitlyOverdue(comment) {
+				return &contracts.Violation{
+					Rule:     contracts.RuleOverdue,
+					Severity: contracts.SeverityError,
+					Message: fmt.Sprintf(
+						"%s added %s by %s, undated, expired after %s",
+						comment.Prefix,
+						comment.Blame.Date.Format(blameDateLayout),
+						comment.Blame.Author,
+						formatDays(me.ImplicitExpiry),
+					),
+				}
+			}
+			return nil
+		}
 		if me.Strict {
-			return fmt.Errorf("%s missing expiry date", comment.Prefix)
+			return &contracts.Violation{
+				Rule:     contracts.RuleMissingExpiry,
+				Severity: contracts.SeverityError,
+				Message:  fmt.Sprintf("%s missing expiry date", comment.Prefix),
+			}
 		}
 		return nil
 	}
 
 	if me.Now.After(*comment.Expiry) {
 		duration := me.Now.Sub(*comment.Expiry)
-		return fmt.Errorf("%s now overdue for %s", comment.Prefix, durafmt.Parse(duration).LimitFirstN(2))
+		return &contracts.Violation{
+			Rule:     contracts.RuleOverdue,
+			Severity: contracts.SeverityError,
+			Message:  fmt.Sprintf("%s now overdue for %s", comment.Prefix, durafmt.Parse(duration).LimitFirstN(2)),
+		}
 	}
 	return nil
 }
+
+// formatDays prefers days over weeks, as ImplicitExpiry is usually configured in days
+func formatDays(duration time.Duration) string {
+	day := 24 * time.Hour
+	if duration%day == 0 {
+		return fmt.Sprintf("%d days", duration/day)
+	}
+	return durafmt.Parse(duration).LimitFirstN(2).String()
+}
+
+// implicitlyOverdue tells if an undated comment is older than ImplicitExpiry, comments which are not committed yet
+// are considered new
+func (me *enforcer) implicitlyOverdue(comment contracts.ParsedComment) bool {
+	return me.ImplicitExpiry > 0 && comment.Blame != nil && me.Now.After(comment.Blame.Date.Add(me.ImplicitExpiry))
+}
+
+func (me *enforcer) Status(comment contracts.ParsedComment) contracts.Status {
+	switch {
+	case comment.InvalidExpiry != "":
+		return contracts.StatusInvalid
+	case comment.Expiry == nil && me.implicitlyOverdue(comment):
+		return contracts.StatusOverdue
+	case comment.Expiry == nil:
+		return contracts.StatusUndated
+	case me.Now.After(*comment.Expiry):
+		return contracts.StatusOverdue
+	case comment.Expiry.Sub(me.Now) <= me.WarningPeriod:
+		return contracts.StatusWarning
+	}
+	return contracts.StatusOK
+}
@@ -1,22 +1,26 @@
 package enforcer
 
 import (
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/LouisBrunner/gofixit/src/contracts"
 	"github.com/LouisBrunner/gofixit/src/utils"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_Check(t *testing.T) {
 	now := time.Now()
 
 	tests := []struct {
-		name    string
-		config  contracts.EnforcerConfig
-		comment contracts.ParsedComment
-		wantErr bool
+		name        string
+		config      contracts.EnforcerConfig
+		comment     contracts.ParsedComment
+		wantErr     bool
+		wantRule    contracts.Rule
+		wantMessage string
 	}{
 		{
 			name: "works, no expiry, not strict",
@@ -44,7 +48,8 @@ func Test_Check(t *testing.T) {
 				LineNumber:    5,
 				OriginalLine:  "// fixit: implement",
 			},
-			wantErr: true,
+			wantErr:  true,
+			wantRule: contracts.RuleMissingExpiry,
 		},
 		{
 			name: "works, valid expiry, not strict",
@@ -88,7 +93,8 @@ func Test_Check(t *testing.T) {
 				LineNumber:    5,
 				OriginalLine:  "// fixit: implement",
 			},
-			wantErr: true,
+			wantErr:  true,
+			wantRule: contracts.RuleOverdue,
 		},
 		{
 			name: "fails, invalid expiry, strict",
@@ -104,7 +110,122 @@ func Test_Check(t *testing.T) {
 				LineNumber:    5,
 				OriginalLine:  "// fixit: implement",
 			},
-			wantErr: true,
+			wantErr:  true,
+			wantRule: contracts.RuleOverdue,
+		},
+		{
+			name: "fails, invalid expiry date",
+			config: contracts.EnforcerConfig{
+				Now: now,
+			},
+			comment: contracts.ParsedComment{
+				CommentPrefix: "//",
+				Prefix:        "fixit",
+				Content:       "implement",
+				InvalidExpiry: "2022-13-45",
+				LineNumber:    5,
+				OriginalLine:  "// fixit[2022-13-45]: implement",
+			},
+			wantErr:  true,
+			wantRule: contracts.RuleInvalidDate,
+		},
+		{
+			name: "works, snoozed within the limit",
+			config: contracts.EnforcerConfig{
+				Now:        now,
+				MaxSnoozes: 2,
+			},
+			comment: contracts.ParsedComment{
+				CommentPrefix: "//",
+				Prefix:        "fixit",
+				Content:       "implement",
+				Expiry:        utils.Pointerize(now.Add(time.Hour)),
+				Metadata:      map[string]string{contracts.MetadataSnoozed: "2"},
+				LineNumber:    5,
+			},
+		},
+		{
+			name: "works, snoozed without a limit",
+			config: contracts.EnforcerConfig{
+				Now: now,
+			},
+			comment: contracts.ParsedComment{
+				CommentPrefix: "//",
+				Prefix:        "fixit",
+				Content:       "implement",
+				Expiry:        utils.Pointerize(now.Add(time.Hour)),
+				Metadata:      map[string]string{contracts.MetadataSnoozed: "10"},
+				LineNumber:    5,
+			},
+		},
+		{
+			name: "fails, snoozed too many times",
+			config: contracts.EnforcerConfig{
+				Now:        now,
+				MaxSnoozes: 2,
+			},
+			comment: contracts.ParsedComment{
+				CommentPrefix: "//",
+				Prefix:        "fixit",
+				Content:       "implement",
+				Expiry:        utils.Pointerize(now.Add(time.Hour)),
+				Metadata:      map[string]string{contracts.MetadataSnoozed: "3"},
+				LineNumber:    5,
+			},
+			wantErr:  true,
+			wantRule: contracts.RuleSnoozeLimit,
+		},
+		{
+			name: "works, no expiry, strict, implicit expiry not reached",
+			config: contracts.EnforcerConfig{
+				Now:            now,
+				Strict:         true,
+				ImplicitExpiry: 90 * 24 * time.Hour,
+			},
+			comment: contracts.ParsedComment{
+				CommentPrefix: "//",
+				Prefix:        "fixit",
+				Content:       "implement",
+				LineNumber:    5,
+				Blame: &contracts.Blame{
+					Author: "alice",
+					Date:   now.Add(-89 * 24 * time.Hour),
+				},
+			},
+		},
+		{
+			name: "works, no expiry, strict, implicit expiry not committed",
+			config: contracts.EnforcerConfig{
+				Now:            now,
+				Strict:         true,
+				ImplicitExpiry: 90 * 24 * time.Hour,
+			},
+			comment: contracts.ParsedComment{
+				CommentPrefix: "//",
+				Prefix:        "fixit",
+				Content:       "implement",
+				LineNumber:    5,
+			},
+		},
+		{
+			name: "fails, no expiry, implicit expiry reached",
+			config: contracts.EnforcerConfig{
+				Now:            now,
+				ImplicitExpiry: 90 * 24 * time.Hour,
+			},
+			comment: contracts.ParsedComment{
+				CommentPrefix: "//",
+				Prefix:        "TODO",
+				Content:       "implement",
+				LineNumber:    5,
+				Blame: &contracts.Blame{
+					Author: "alice",
+					Date:   time.Date(2023, 1, 4, 10, 0, 0, 0, time.UTC),
+				},
+			},
+			wantErr:     true,
+			wantRule:    contracts.RuleOverdue,
+			wantMessage: "TODO added 2023-01-04 by alice, undated, expired after 90 days",
 		},
 	}
 	for _, tt := range tests {
@@ -118,6 +239,244 @@ func Test_Check(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if tt.wantErr {
+				var violation *contracts.Violation
+				if !errors.As(err, &violation) {
+					t.Fatalf("expected a violation, got %v", err)
+				}
+				assert.Equal(t, tt.wantRule, violation.Rule)
+				assert.Equal(t, contracts.SeverityError, violation.Severity)
+				if tt.wantMessage != "" {
+					assert.Equal(t, tt.wantMessage, violation.Message)
+				}
+			}
+		})
+	}
+}
+
+func Test_Status(t *testing.T) {
+	now := time.Now()
+	config := contracts.EnforcerConfig{
+		Now:           now,
+		WarningPeriod: 24 * time.Hour,
+	}
+	implicit := config
+	implicit.ImplicitExpiry = 90 * 24 * time.Hour
+
+	tests := []struct {
+		name    string
+		config  *contracts.EnforcerConfig
+		comment contracts.ParsedComment
+		status  contracts.Status
+	}{
+		{
+			name: "ok",
+			comment: contracts.ParsedComment{
+				Prefix: "fixit",
+				Expiry: utils.Pointerize(now.Add(48 * time.Hour)),
+			},
+			status: contracts.StatusOK,
+		},
+		{
+			name: "warning",
+			comment: contracts.ParsedComment{
+				Prefix: "fixit",
+				Expiry: utils.Pointerize(now.Add(time.Hour)),
+			},
+			status: contracts.StatusWarning,
+		},
+		{
+			name: "overdue",
+			comment: contracts.ParsedComment{
+				Prefix: "fixit",
+				Expiry: utils.Pointerize(now.Add(-time.Hour)),
+			},
+			status: contracts.StatusOverdue,
+		},
+		{
+			name: "undated",
+			comment: contracts.ParsedComment{
+				Prefix: "fixit",
+			},
+			status: contracts.StatusUndated,
+		},
+		{
+			name: "invalid",
+			comment: contracts.ParsedComment{
+				Prefix:        "fixit",
+				InvalidExpiry: "2022-13-45",
+			},
+			status: contracts.StatusInvalid,
+		},
+		{
+			name:   "overdue, implicit expiry",
+			config: &implicit,
+			comment: contracts.ParsedComment{
+				Prefix: "fixit",
+				Blame:  &contracts.Blame{Date: now.Add(-91 * 24 * time.Hour)},
+			},
+			status: contracts.StatusOverdue,
+		},
+		{
+			name:   "undated, implicit expiry not reached",
+			config: &implicit,
+			comment: contracts.ParsedComment{
+				Prefix: "fixit",
+				Blame:  &contracts.Blame{Date: now.Add(-89 * 24 * time.Hour)},
+			},
+			status: contracts.StatusUndated,
+		},
+		{
+			name:   "undated, implicit expiry without blame",
+			config: &implicit,
+			comment: contracts.ParsedComment{
+				Prefix: "fixit",
+			},
+			status: contracts.StatusUndated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := config
+			if tt.config != nil {
+				config = *tt.config
+			}
+			me, err := New(logrus.New(), config)
+			if err != nil {
+				t.Fatalf("failed to create enforcer: %v", err)
+			}
+
+			assert.Equal(t, tt.status, me.Status(tt.comment))
+		})
+	}
+}
+
+func Test_Suppress(t *testing.T) {
+	violation := func(line uint, rule contracts.Rule) contracts.Result {
+		return contracts.Result{
+			Path:    "main.c",
+			Comment: contracts.ParsedComment{Prefix: "TODO", LineNumber: line},
+			Violation: &contracts.Violation{
+				Rule:     rule,
+				Severity: contracts.SeverityError,
+			},
+		}
+	}
+	suppression := func(kind contracts.SuppressionKind, line uint, rule contracts.Rule) contracts.Suppression {
+		return contracts.Suppression{Kind: kind, Rule: rule, Reason: "because", LineNumber: line}
+	}
+	results := []contracts.Result{
+		violation(2, contracts.RuleOverdue),
+		violation(5, contracts.RuleMissingExpiry),
+		violation(6, contracts.RuleOverdue),
+		violation(9, contracts.RuleOverdue),
+		{Path: "main.c", Comment: contracts.ParsedComment{Prefix: "TODO", LineNumber: 12}},
+	}
+
+	type problem struct {
+		line    uint
+		rule    contracts.Rule
+		message string
+	}
+	tests := []struct {
+		name         string
+		unused       bool
+		suppressions []contracts.Suppression
+		remaining    []uint
+		problems     []problem
+	}{
+		{
+			name:      "works without suppressions",
+			remaining: []uint{2, 5, 6, 9},
+		},
+		{
+			name: "ignores the same or the next line",
+			suppressions: []contracts.Suppression{
+				suppression(contracts.SuppressionIgnore, 1, ""),
+				suppression(contracts.SuppressionIgnore, 5, ""),
+				suppression(contracts.SuppressionIgnore, 8, contracts.RuleMissingExpiry),
+			},
+			remaining: []uint{6, 9},
+		},
+		{
+			name: "ignores the whole file",
+			suppressions: []contracts.Suppression{
+				suppression(contracts.SuppressionIgnoreFile, 20, contracts.RuleOverdue),
+			},
+			remaining: []uint{5},
+		},
+		{
+			name: "disables ranges",
+			suppressions: []contracts.Suppression{
+				suppression(contracts.SuppressionDisable, 3, ""),
+				suppression(contracts.SuppressionEnable, 7, ""),
+				suppression(contracts.SuppressionDisable, 8, contracts.RuleOverdue),
+			},
+			remaining: []uint{2},
+		},
+		{
+			name: "reports invalid suppressions",
+			suppressions: []contracts.Suppression{
+				{Kind: contracts.SuppressionIgnore, Invalid: "missing reason", LineNumber: 1},
+				suppression(contracts.SuppressionIgnore, 4, "unknown"),
+				suppression(contracts.SuppressionDisable, 4, ""),
+				suppression(contracts.SuppressionDisable, 5, ""),
+				suppression(contracts.SuppressionEnable, 7, ""),
+				suppression(contracts.SuppressionEnable, 8, contracts.RuleOverdue),
+			},
+			remaining: []uint{2, 9},
+			problems: []problem{
+				{1, contracts.RuleInvalidSuppression, "missing reason"},
+				{4, contracts.RuleInvalidSuppression, "gofixit:ignore unknown uses an unknown rule"},
+				{5, contracts.RuleInvalidSuppression, "gofixit:disable already applies since line 4"},
+				{8, contracts.RuleInvalidSuppression, "gofixit:enable overdue without a matching gofixit:disable"},
+			},
+		},
+		{
+			name:   "reports unused suppressions",
+			unused: true,
+			suppressions: []contracts.Suppression{
+				suppression(contracts.SuppressionIgnore, 1, ""),
+				suppression(contracts.SuppressionIgnore, 3, ""),
+				suppression(contracts.SuppressionIgnore, 11, ""),
+				suppression(contracts.SuppressionDisable, 4, contracts.RuleInvalidDate),
+				suppression(contracts.SuppressionEnable, 10, contracts.RuleInvalidDate),
+				suppression(contracts.SuppressionIgnoreFile, 1, contracts.RuleMissingExpiry),
+			},
+			remaining: []uint{6, 9},
+			problems: []problem{
+				{3, contracts.RuleUnusedSuppression, "gofixit:ignore does not suppress any violation"},
+				{4, contracts.RuleUnusedSuppression, "gofixit:disable invalid-date does not suppress any violation"},
+				{11, contracts.RuleUnusedSuppression, "gofixit:ignore does not suppress any violation"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			me, err := New(logrus.New(), contracts.EnforcerConfig{
+				ReportUnusedSuppressions: tt.unused,
+			})
+			if err != nil {
+				t.Fatalf("failed to create enforcer: %v", err)
+			}
+
+			updated, problems := me.Suppress("main.c", results, tt.suppressions)
+			remaining := []uint{}
+			for _, result := range updated {
+				if result.Violation != nil {
+					remaining = append(remaining, result.Comment.LineNumber)
+				}
+			}
+			assert.Equal(t, tt.remaining, remaining)
+			got := []problem{}
+			for _, result := range problems {
+				assert.Equal(t, "main.c", result.Path)
+				got = append(got, problem{result.Comment.LineNumber, result.Violation.Rule, result.Violation.Message})
+			}
+			if tt.problems == nil {
+				tt.problems = []problem{}
+			}
+			assert.Equal(t, tt.problems, got)
 		})
 	}
 }
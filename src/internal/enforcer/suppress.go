@@ -0,0 +1,135 @@
+package enforcer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+)
+
+var suppressibleRules = map[contracts.Rule]bool{
+	contracts.RuleOverdue:       true,
+	contracts.RuleMissingExpiry: true,
+	contracts.RuleInvalidDate:   true,
+	contracts.RuleSnoozeLimit:   true,
+}
+
+// span is the range of lines covered by a suppression, until the end of the file when to is 0
+type span struct {
+	suppression contracts.Suppression
+	from        uint
+	to          uint
+	used        bool
+}
+
+func (me *span) covers(line uint, rule contracts.Rule) bool {
+	if me.suppression.Rule != "" && me.suppression.Rule != rule {
+		return false
+	}
+	return line >= me.from && (me.to == 0 || line <= me.to)
+}
+
+func directive(suppression contracts.Suppression) string {
+	name := contracts.SuppressionDirective + string(suppression.Kind)
+	if suppression.Rule != "" {
+		name += " " + string(suppression.Rule)
+	}
+	return name
+}
+
+func (me *enforcer) Suppress(path string, results []contracts.Result, suppressions []contracts.Suppression) ([]contracts.Result, []contracts.Result) {
+	problems := []contracts.Result{}
+	report := func(suppression contracts.Suppression, rule contracts.Rule, message string) {
+		problems = append(problems, contracts.Result{
+			Path: path,
+			Comment: contracts.ParsedComment{
+				Prefix:       contracts.SuppressionDirective + string(suppression.Kind),
+				Content:      suppression.Reason,
+				LineNumber:   suppression.LineNumber,
+				Column:       suppression.Column,
+				OriginalLine: suppression.OriginalLine,
+				SourceLine:   suppression.SourceLine,
+			},
+			Violation: &contracts.Violation{
+				Rule:     rule,
+				Severity: contracts.SeverityError,
+				Message:  message,
+			},
+			Status: contracts.StatusInvalid,
+		})
+	}
+
+	commented := map[uint]bool{}
+	for _, result := range results {
+		commented[result.Comment.LineNumber] = true
+	}
+
+	spans := []*span{}
+	disabled := map[contracts.Rule]*span{}
+	for _, suppression := range suppressions {
+		if suppression.Invalid != "" {
+			report(suppression, contracts.RuleInvalidSuppression, suppression.Invalid)
+			continue
+		}
+		if suppression.Rule != "" && !suppressibleRules[suppression.Rule] {
+			report(suppression, contracts.RuleInvalidSuppression, fmt.Sprintf("%s uses an unknown rule", directive(suppression)))
+			continue
+		}
+
+		switch suppression.Kind {
+		case contracts.SuppressionIgnore:
+			line := suppression.LineNumber
+			if !commented[line] {
+				line += 1
+			}
+			spans = append(spans, &span{suppression: suppression, from: line, to: line})
+		case contracts.SuppressionIgnoreFile:
+			spans = append(spans, &span{suppression: suppression, from: 1})
+		case contracts.SuppressionDisable:
+			if previous, found := disabled[suppression.Rule]; found {
+				report(suppression, contracts.RuleInvalidSuppression, fmt.Sprintf("%s already applies since line %d", directive(suppression), previous.suppression.LineNumber))
+				continue
+			}
+			disabled[suppression.Rule] = &span{suppression: suppression, from: suppression.LineNumber}
+			spans = append(spans, disabled[suppression.Rule])
+		case contracts.SuppressionEnable:
+			previous, found := disabled[suppression.Rule]
+			if !found {
+				report(suppression, contracts.RuleInvalidSuppression, fmt.Sprintf("%s without a matching %sdisable", directive(suppression), contracts.SuppressionDirective))
+				continue
+			}
+			previous.to = suppression.LineNumber
+			delete(disabled, suppression.Rule)
+		}
+	}
+
+	updated := make([]contracts.Result, 0, len(results))
+	for _, result := range results {
+		if result.Violation != nil {
+			suppressed := false
+			for _, span := range spans {
+				if span.covers(result.Comment.LineNumber, result.Violation.Rule) {
+					me.logger.Debugf("%s:%d suppressed by %s on line %d", path, result.Comment.LineNumber, directive(span.suppression), span.suppression.LineNumber)
+					span.used = true
+					suppressed = true
+				}
+			}
+			if suppressed {
+				result.Violation = nil
+			}
+		}
+		updated = append(updated, result)
+	}
+
+	if me.ReportUnusedSuppressions {
+		for _, span := range spans {
+			if !span.used {
+				report(span.suppression, contracts.RuleUnusedSuppression, fmt.Sprintf("%s does not suppress any violation", directive(span.suppression)))
+			}
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Comment.LineNumber < problems[j].Comment.LineNumber
+	})
+	return updated, problems
+}
@@ -0,0 +1,169 @@
+package files
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+)
+
+const archiveSeparator = "!/"
+
+const defaultMaxArchiveMemberSize = 64 << 20
+
+type archiveKind int
+
+const (
+	archiveNone archiveKind = iota
+	archiveZip
+	archiveTar
+	archiveTarGz
+)
+
+func detectArchive(name string) archiveKind {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"), strings.HasSuffix(lower, ".jar"):
+		return archiveZip
+	case strings.HasSuffix(lower, ".tar"):
+		return archiveTar
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return archiveTarGz
+	}
+	return archiveNone
+}
+
+// cappedReader fails once more than max bytes were read, so a single member cannot exhaust the memory
+type cappedReader struct {
+	reader io.Reader
+	read   int64
+	max    int64
+}
+
+func newCappedReader(content io.Reader, max int64) *cappedReader {
+	return &cappedReader{reader: io.LimitReader(content, max+1), max: max}
+}
+
+func (me *cappedReader) Read(p []byte) (int, error) {
+	n, err := me.reader.Read(p)
+	me.read += int64(n)
+	if me.read > me.max {
+		return n, contracts.ErrArchiveMemberTooLarge
+	}
+	return n, err
+}
+
+func (me *fprocessor[T]) processArchive(kind archiveKind, filename, name, absFilename string, state *walk[T]) error {
+	file, err := me.FS.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	visit := func(member string, size int64, content io.Reader) error {
+		member = strings.TrimPrefix(path.Clean("/"+member), "/")
+		display := filename + archiveSeparator + member
+		if me.excluded(display, absFilename+archiveSeparator+member) {
+			return nil
+		}
+		var err error
+		if size > me.MaxArchiveMemberSize {
+			err = contracts.ErrArchiveMemberTooLarge
+		} else {
+			var result T
+			result, err = me.Processor(display, newCappedReader(content, me.MaxArchiveMemberSize))
+			if err == nil {
+				state.results[display] = result
+				return nil
+			}
+		}
+		err = fmt.Errorf("failed to process %s: %w", display, err)
+		if !me.KeepGoing || !errors.Is(err, contracts.ErrArchiveMemberTooLarge) {
+			return err
+		}
+		// the rest of the archive can still be processed
+		me.logger.Warnf("skipping %s: %v", display, err)
+		state.failures[display] = err
+		return nil
+	}
+
+	switch kind {
+	case archiveZip:
+		return me.walkZip(file, visit)
+	case archiveTarGz:
+		uncompressed, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer uncompressed.Close()
+		return me.walkTar(uncompressed, visit)
+	default:
+		return me.walkTar(file, visit)
+	}
+}
+
+func (me *fprocessor[T]) walkZip(file fs.File, visit func(member string, size int64, content io.Reader) error) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	readerAt, ok := file.(io.ReaderAt)
+	if !ok {
+		content, err := io.ReadAll(file)
+		if err != nil {
+			return err
+		}
+		readerAt = bytes.NewReader(content)
+	}
+
+	archive, err := zip.NewReader(readerAt, info.Size())
+	if err != nil {
+		return err
+	}
+	for _, member := range archive.File {
+		if member.FileInfo().IsDir() {
+			continue
+		}
+		content, err := member.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", member.Name, err)
+		}
+		err = visit(member.Name, int64(utils.Min(member.UncompressedSize64, math.MaxInt64)), content)
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (me *fprocessor[T]) walkTar(file io.Reader, visit func(member string, size int64, content io.Reader) error) error {
+	archive := tar.NewReader(file)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !header.FileInfo().Mode().IsRegular() {
+			me.logger.Debugf("skipping %s (type %q)", header.Name, header.Typeflag)
+			continue
+		}
+		err = visit(header.Name, header.Size, archive)
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows || plan9
+
+package files
+
+import "io/fs"
+
+func identity(info fs.FileInfo) (string, bool) {
+	return "", false
+}
@@ -0,0 +1,17 @@
+//go:build !windows && !plan9
+
+package files
+
+import (
+	"fmt"
+	"io/fs"
+	"syscall"
+)
+
+func identity(info fs.FileInfo) (string, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("%d:%d", stat.Dev, stat.Ino), true
+}
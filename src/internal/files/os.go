@@ -0,0 +1,32 @@
+package files
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// lstatFS is implemented by filesystems which support symlinks
+type lstatFS interface {
+	fs.FS
+	Lstat(name string) (fs.FileInfo, error)
+}
+
+type osFS struct {
+	fs.FS
+	root string
+}
+
+func newOSFS(root string) lstatFS {
+	return &osFS{
+		FS:   os.DirFS(root),
+		root: root,
+	}
+}
+
+func (me *osFS) Lstat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: fs.ErrInvalid}
+	}
+	return os.Lstat(filepath.Join(me.root, filepath.FromSlash(name)))
+}
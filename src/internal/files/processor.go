@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"strings"
 
 	"github.com/LouisBrunner/gofixit/src/contracts"
 	"github.com/LouisBrunner/gofixit/src/utils"
@@ -16,6 +18,7 @@ type fprocessor[T any] struct {
 	contracts.FilesProcessorConfig[T]
 	logger          *logrus.Logger
 	excludePatterns []regexp.Regexp
+	root            string
 }
 
 func New[T any](logger *logrus.Logger, config contracts.FilesProcessorConfig[T]) (contracts.FilesProcessor[T], error) {
@@ -28,66 +31,198 @@ func New[T any](logger *logrus.Logger, config contracts.FilesProcessorConfig[T])
 		excludePatterns = append(excludePatterns, *re)
 	}
 
+	if config.MaxArchiveMemberSize <= 0 {
+		config.MaxArchiveMemberSize = defaultMaxArchiveMemberSize
+	}
+
+	root := ""
+	if config.FS == nil {
+		pwd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		root = filepath.VolumeName(pwd) + string(filepath.Separator)
+		config.FS = newOSFS(root)
+	}
+
 	return &fprocessor[T]{
 		FilesProcessorConfig: config,
 		logger:               logger,
 		excludePatterns:      excludePatterns,
+		root:                 root,
 	}, nil
 }
 
-func (me *fprocessor[T]) ProcessFiles(files []string) (map[string]T, error) {
-	results := make(map[string]T, len(files))
-	absMatches := make(map[string]struct{}, len(files))
-
-	extras := []string{}
-	for len(files) > 0 {
-	fileLoop:
-		for _, filename := range files {
-			absFilename, err := filepath.Abs(filename)
-			if err != nil {
-				return nil, fmt.Errorf("failed to generate absolute path for %s: %w", filename, err)
+// resolve returns the name of the file inside the filesystem and its absolute path (as used for exclusions)
+func (me *fprocessor[T]) resolve(filename string) (string, string, error) {
+	if me.root == "" {
+		name := path.Clean(filepath.ToSlash(filename))
+		if !fs.ValidPath(name) {
+			return "", "", fmt.Errorf("invalid path %q", filename)
+		}
+		return name, path.Join("/", name), nil
+	}
+
+	absFilename, err := filepath.Abs(filename)
+	if err != nil {
+		return "", "", err
+	}
+	rel, err := filepath.Rel(me.root, absFilename)
+	if err != nil {
+		return "", "", err
+	}
+	name := filepath.ToSlash(rel)
+	if name == ".." || strings.HasPrefix(name, "../") {
+		return "", "", fmt.Errorf("%s is outside of %s", absFilename, me.root)
+	}
+	return name, absFilename, nil
+}
+
+func (me *fprocessor[T]) excluded(needles ...string) bool {
+	for _, needle := range needles {
+		for _, excludePattern := range me.excludePatterns {
+			if excludePattern.MatchString(needle) {
+				return true
 			}
-			if _, found := absMatches[absFilename]; found {
+		}
+	}
+	return false
+}
+
+func (me *fprocessor[T]) process(filename, name string) (T, error) {
+	file, err := me.FS.Open(name)
+	if err != nil {
+		var empty T
+		return empty, err
+	}
+	defer file.Close()
+	return me.Processor(filename, file)
+}
+
+type pending struct {
+	filename string
+	explicit bool
+	// ancestors contains the identity of every directory leading to this file, used to detect loops
+	ancestors []string
+}
+
+type walk[T any] struct {
+	results    map[string]T
+	absMatches map[string]struct{}
+	extras     []pending
+	failures   contracts.FilesErrors
+}
+
+func (me *fprocessor[T]) ProcessFiles(files []string) (map[string]T, error) {
+	state := &walk[T]{
+		results:    make(map[string]T, len(files)),
+		absMatches: make(map[string]struct{}, len(files)),
+		extras:     []pending{},
+		failures:   contracts.FilesErrors{},
+	}
+
+	queue := utils.MapSlice(files, func(filename string) pending {
+		return pending{filename: filename, explicit: true}
+	})
+	for len(queue) > 0 {
+		for _, entry := range queue {
+			err := me.processFile(entry, state)
+			if err == nil {
 				continue
 			}
-
-			for _, needle := range []string{filename, absFilename} {
-				for _, excludePattern := range me.excludePatterns {
-					if excludePattern.MatchString(needle) {
-						continue fileLoop
-					}
-				}
+			if !me.KeepGoing {
+				return nil, err
 			}
+			me.logger.Warnf("skipping %s: %v", entry.filename, err)
+			state.failures[entry.filename] = err
+		}
+		queue = state.extras
+		state.extras = []pending{}
+	}
 
-			info, err := os.Stat(filename)
-			if err == nil {
-				if !info.IsDir() {
-					result, err := me.Processor(filename)
-					if err != nil {
-						return nil, fmt.Errorf("failed to process %s: %w", filename, err)
-					}
-					results[filename] = result
-					absMatches[absFilename] = struct{}{}
-				} else if me.Recursive {
-					files, err := os.ReadDir(filename)
-					if err != nil {
-						return nil, fmt.Errorf("failed to list %s: %w", filename, err)
-					}
-					filenames := utils.MapSlice(files, func(entry fs.DirEntry) string {
-						return filepath.Join(filename, entry.Name())
-					})
-					extras = append(extras, filenames...)
-				} else {
-					return nil, fmt.Errorf("cannot process directory %s: %w", filename, err)
-				}
+	if len(state.failures) > 0 {
+		return state.results, state.failures
+	}
+	return state.results, nil
+}
 
-				continue
+func (me *fprocessor[T]) lstat(name string) (fs.FileInfo, error) {
+	if links, ok := me.FS.(lstatFS); ok {
+		return links.Lstat(name)
+	}
+	return fs.Stat(me.FS, name)
+}
+
+func (me *fprocessor[T]) processFile(entry pending, state *walk[T]) error {
+	filename := entry.filename
+	name, absFilename, err := me.resolve(filename)
+	if err != nil {
+		return fmt.Errorf("failed to generate absolute path for %s: %w", filename, err)
+	}
+	if _, found := state.absMatches[absFilename]; found {
+		return nil
+	}
+
+	if me.excluded(filename, absFilename) {
+		return nil
+	}
+
+	linkInfo, err := me.lstat(name)
+	if err != nil {
+		return fmt.Errorf("no such file %s: %w", filename, err)
+	}
+	isLink := linkInfo.Mode()&fs.ModeSymlink != 0
+	if isLink && !entry.explicit && me.SkipSymlinks {
+		me.logger.Debugf("skipping symlink %s", filename)
+		return nil
+	}
+
+	info, err := fs.Stat(me.FS, name)
+	if err != nil {
+		if isLink {
+			return fmt.Errorf("cannot follow %s: %w", filename, contracts.ErrBrokenSymlink)
+		}
+		return fmt.Errorf("no such file %s: %w", filename, err)
+	}
+
+	if kind := detectArchive(name); !info.IsDir() && me.ScanArchives && kind != archiveNone {
+		state.absMatches[absFilename] = struct{}{}
+		err := me.processArchive(kind, filename, name, absFilename, state)
+		if err != nil {
+			return fmt.Errorf("failed to process archive %s: %w", filename, err)
+		}
+	} else if !info.IsDir() {
+		state.absMatches[absFilename] = struct{}{}
+		result, err := me.process(filename, name)
+		if err != nil {
+			return fmt.Errorf("failed to process %s: %w", filename, err)
+		}
+		state.results[filename] = result
+	} else if me.Recursive {
+		id, found := identity(info)
+		if !found {
+			id = absFilename
+		}
+		for _, ancestor := range entry.ancestors {
+			if ancestor == id {
+				me.logger.Warnf("skipping %s: directory loop detected", filename)
+				return nil
 			}
+		}
+		ancestors := append(append(make([]string, 0, len(entry.ancestors)+1), entry.ancestors...), id)
 
-			return nil, fmt.Errorf("no such file %s: %w", filename, err)
+		files, err := fs.ReadDir(me.FS, name)
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", filename, err)
 		}
-		files = extras
-		extras = []string{}
+		state.extras = append(state.extras, utils.MapSlice(files, func(child fs.DirEntry) pending {
+			return pending{
+				filename:  filepath.Join(filename, child.Name()),
+				ancestors: ancestors,
+			}
+		})...)
+	} else {
+		return fmt.Errorf("cannot process directory %s", filename)
 	}
-	return results, nil
+	return nil
 }
@@ -1,12 +1,21 @@
 package files
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
@@ -40,7 +49,7 @@ func Test_New(t *testing.T) {
 }
 
 func Test_ProcessFiles_echo(t *testing.T) {
-	echoProcessor := func(filepath string) (string, error) {
+	echoProcessor := func(filepath string, content io.Reader) (string, error) {
 		return filepath, nil
 	}
 
@@ -49,6 +58,11 @@ func Test_ProcessFiles_echo(t *testing.T) {
 		t.Fatalf("could not read working directory: %v", err)
 	}
 
+	memFS := fstest.MapFS{
+		"testdata/file.c":      &fstest.MapFile{Data: []byte("int main;")},
+		"testdata/sub/file2.c": &fstest.MapFile{Data: []byte("int other;")},
+	}
+
 	tests := []struct {
 		name    string
 		config  contracts.FilesProcessorConfig[string]
@@ -74,6 +88,7 @@ func Test_ProcessFiles_echo(t *testing.T) {
 			name: "works with relative files & directories",
 			config: contracts.FilesProcessorConfig[string]{
 				Processor: echoProcessor,
+				FS:        memFS,
 				Recursive: true,
 			},
 			inputs: []string{
@@ -132,9 +147,10 @@ func Test_ProcessFiles_echo(t *testing.T) {
 		{
 			name: "report processor failure",
 			config: contracts.FilesProcessorConfig[string]{
-				Processor: func(filepath string) (string, error) {
+				Processor: func(filepath string, content io.Reader) (string, error) {
 					return "", fmt.Errorf("failed")
 				},
+				FS:        memFS,
 				Recursive: true,
 			},
 			inputs: []string{
@@ -146,6 +162,7 @@ func Test_ProcessFiles_echo(t *testing.T) {
 			name: "fail with missing files",
 			config: contracts.FilesProcessorConfig[string]{
 				Processor: echoProcessor,
+				FS:        memFS,
 				Recursive: true,
 			},
 			inputs: []string{
@@ -157,16 +174,44 @@ func Test_ProcessFiles_echo(t *testing.T) {
 			name: "fail with directories without a flag",
 			config: contracts.FilesProcessorConfig[string]{
 				Processor: echoProcessor,
+				FS:        memFS,
 			},
 			inputs: []string{
 				"testdata/sub",
 			},
 			wantErr: true,
 		},
+		{
+			name: "fail with absolute paths on a custom filesystem",
+			config: contracts.FilesProcessorConfig[string]{
+				Processor: echoProcessor,
+				FS:        memFS,
+			},
+			inputs: []string{
+				"/testdata/file.c",
+			},
+			wantErr: true,
+		},
+		{
+			name: "works with the root of a custom filesystem",
+			config: contracts.FilesProcessorConfig[string]{
+				Processor: echoProcessor,
+				FS:        memFS,
+				Recursive: true,
+			},
+			inputs: []string{
+				".",
+			},
+			want: map[string]string{
+				"testdata/file.c":      "testdata/file.c",
+				"testdata/sub/file2.c": "testdata/sub/file2.c",
+			},
+		},
 		{
 			name: "works with relative file exclusion",
 			config: contracts.FilesProcessorConfig[string]{
 				Processor: echoProcessor,
+				FS:        memFS,
 				Recursive: true,
 				FilesExcludePatterns: []string{
 					"/file.c$",
@@ -212,3 +257,425 @@ func Test_ProcessFiles_echo(t *testing.T) {
 		})
 	}
 }
+
+func Test_ProcessFiles_content(t *testing.T) {
+	processor, err := New(logrus.New(), contracts.FilesProcessorConfig[string]{
+		Processor: func(filepath string, content io.Reader) (string, error) {
+			data, err := io.ReadAll(content)
+			return string(data), err
+		},
+		Recursive: true,
+		FS: fstest.MapFS{
+			"main.c":      &fstest.MapFile{Data: []byte("int main;")},
+			"sub/other.c": &fstest.MapFile{Data: []byte("int other;")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("could not create processor: %v", err)
+	}
+
+	got, err := processor.ProcessFiles([]string{"."})
+	if err != nil {
+		t.Fatalf("could not process files: %v", err)
+	}
+	assert.Equal(t, map[string]string{
+		"main.c":      "int main;",
+		"sub/other.c": "int other;",
+	}, got)
+}
+
+func Test_ProcessFiles_archives(t *testing.T) {
+	members := map[string]string{
+		"src/main.c":   "int main;",
+		"src/other.c":  "int other;",
+		"docs/todo.md": "later",
+	}
+
+	zipContent := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(zipContent)
+	tarContent := &bytes.Buffer{}
+	tarWriter := tar.NewWriter(tarContent)
+	for _, entry := range utils.SortedMap(members) {
+		writer, err := zipWriter.Create(entry.Key)
+		if err == nil {
+			_, err = writer.Write([]byte(entry.Value))
+		}
+		if err == nil {
+			err = tarWriter.WriteHeader(&tar.Header{Name: entry.Key, Mode: 0o644, Size: int64(len(entry.Value)), Typeflag: tar.TypeReg})
+		}
+		if err == nil {
+			_, err = tarWriter.Write([]byte(entry.Value))
+		}
+		if err != nil {
+			t.Fatalf("could not write archive member: %v", err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatalf("could not write zip: %v", err)
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("could not write tar: %v", err)
+	}
+	gzContent := &bytes.Buffer{}
+	gzWriter := gzip.NewWriter(gzContent)
+	if _, err := gzWriter.Write(tarContent.Bytes()); err != nil {
+		t.Fatalf("could not write tar.gz: %v", err)
+	}
+	if err := gzWriter.Close(); err != nil {
+		t.Fatalf("could not write tar.gz: %v", err)
+	}
+
+	memFS := fstest.MapFS{
+		"main.c":             &fstest.MapFile{Data: []byte("int main;")},
+		"bundle.zip":         &fstest.MapFile{Data: zipContent.Bytes()},
+		"lib/bundle.jar":     &fstest.MapFile{Data: zipContent.Bytes()},
+		"bundle.tar":         &fstest.MapFile{Data: tarContent.Bytes()},
+		"release/bundle.tgz": &fstest.MapFile{Data: gzContent.Bytes()},
+		"bundle.tar.gz":      &fstest.MapFile{Data: gzContent.Bytes()},
+		"broken.tar.gz":      &fstest.MapFile{Data: []byte("not compressed")},
+	}
+	contentProcessor := func(filepath string, content io.Reader) (string, error) {
+		data, err := io.ReadAll(content)
+		return string(data), err
+	}
+
+	tests := []struct {
+		name    string
+		config  contracts.FilesProcessorConfig[string]
+		inputs  []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "works without scanning archives",
+			config: contracts.FilesProcessorConfig[string]{
+				Processor: contentProcessor,
+				FS:        memFS,
+			},
+			inputs: []string{"main.c", "bundle.tar"},
+			want: map[string]string{
+				"main.c":     "int main;",
+				"bundle.tar": tarContent.String(),
+			},
+		},
+		{
+			name: "works with every archive type",
+			config: contracts.FilesProcessorConfig[string]{
+				Processor:            contentProcessor,
+				FS:                   memFS,
+				Recursive:            true,
+				ScanArchives:         true,
+				FilesExcludePatterns: []string{"^broken", "\\.md$"},
+			},
+			inputs: []string{"."},
+			want: map[string]string{
+				"main.c":                          "int main;",
+				"bundle.zip!/src/main.c":          "int main;",
+				"bundle.zip!/src/other.c":         "int other;",
+				"lib/bundle.jar!/src/main.c":      "int main;",
+				"lib/bundle.jar!/src/other.c":     "int other;",
+				"bundle.tar!/src/main.c":          "int main;",
+				"bundle.tar!/src/other.c":         "int other;",
+				"release/bundle.tgz!/src/main.c":  "int main;",
+				"release/bundle.tgz!/src/other.c": "int other;",
+				"bundle.tar.gz!/src/main.c":       "int main;",
+				"bundle.tar.gz!/src/other.c":      "int other;",
+			},
+		},
+		{
+			name: "works with member exclusion",
+			config: contracts.FilesProcessorConfig[string]{
+				Processor:            contentProcessor,
+				FS:                   memFS,
+				ScanArchives:         true,
+				FilesExcludePatterns: []string{"!/src/other"},
+			},
+			inputs: []string{"bundle.zip"},
+			want: map[string]string{
+				"bundle.zip!/src/main.c":   "int main;",
+				"bundle.zip!/docs/todo.md": "later",
+			},
+		},
+		{
+			name: "fail with a corrupted archive",
+			config: contracts.FilesProcessorConfig[string]{
+				Processor:    contentProcessor,
+				FS:           memFS,
+				ScanArchives: true,
+			},
+			inputs:  []string{"broken.tar.gz"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor, err := New(logrus.New(), tt.config)
+			if err != nil {
+				t.Fatalf("could not create processor: %v", err)
+			}
+
+			got, err := processor.ProcessFiles(tt.inputs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_ProcessFiles_keepGoing(t *testing.T) {
+	processor, err := New(logrus.New(), contracts.FilesProcessorConfig[string]{
+		Processor: func(filepath string, content io.Reader) (string, error) {
+			if filepath == "src/bad.c" {
+				return "", fmt.Errorf("unreadable")
+			}
+			return filepath, nil
+		},
+		Recursive: true,
+		KeepGoing: true,
+		FS: fstest.MapFS{
+			"main.c":    &fstest.MapFile{Data: []byte("int main;")},
+			"src/bad.c": &fstest.MapFile{Data: []byte("int bad;")},
+			"src/ok.c":  &fstest.MapFile{Data: []byte("int ok;")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("could not create processor: %v", err)
+	}
+
+	got, err := processor.ProcessFiles([]string{"main.c", "missing.c", "src"})
+	assert.Equal(t, map[string]string{
+		"main.c":   "main.c",
+		"src/ok.c": "src/ok.c",
+	}, got)
+
+	var failures contracts.FilesErrors
+	if !errors.As(err, &failures) {
+		t.Fatalf("expected FilesErrors, got %v", err)
+	}
+	assert.Len(t, failures, 2)
+	assert.Contains(t, failures, "missing.c")
+	assert.Contains(t, failures, "src/bad.c")
+	assert.Contains(t, err.Error(), "failed to process 2 file(s)")
+}
+
+func Test_ProcessFiles_archiveMemberSize(t *testing.T) {
+	small, big := "int a;", "int main() { return 0; }"
+
+	zipContent := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(zipContent)
+	tarContent := &bytes.Buffer{}
+	tarWriter := tar.NewWriter(tarContent)
+	for _, entry := range utils.SortedMap(map[string]string{"big.c": big, "small.c": small}) {
+		writer, err := zipWriter.Create(entry.Key)
+		if err == nil {
+			_, err = writer.Write([]byte(entry.Value))
+		}
+		if err == nil {
+			err = tarWriter.WriteHeader(&tar.Header{Name: entry.Key, Mode: 0o644, Size: int64(len(entry.Value)), Typeflag: tar.TypeReg})
+		}
+		if err == nil {
+			_, err = tarWriter.Write([]byte(entry.Value))
+		}
+		if err != nil {
+			t.Fatalf("could not write archive member: %v", err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatalf("could not write zip: %v", err)
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("could not write tar: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		keepGoing bool
+		want      map[string]string
+		wantFails []string
+	}{
+		{
+			name:      "reports oversized members as file errors",
+			keepGoing: true,
+			want: map[string]string{
+				"bundle.tar!/small.c": small,
+				"bundle.zip!/small.c": small,
+			},
+			wantFails: []string{"bundle.tar!/big.c", "bundle.zip!/big.c"},
+		},
+		{
+			name: "fails on the first oversized member",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor, err := New(logrus.New(), contracts.FilesProcessorConfig[string]{
+				Processor: func(filepath string, content io.Reader) (string, error) {
+					data, err := io.ReadAll(content)
+					return string(data), err
+				},
+				ScanArchives:         true,
+				MaxArchiveMemberSize: int64(len(small)),
+				KeepGoing:            tt.keepGoing,
+				FS: fstest.MapFS{
+					"bundle.zip": &fstest.MapFile{Data: zipContent.Bytes()},
+					"bundle.tar": &fstest.MapFile{Data: tarContent.Bytes()},
+				},
+			})
+			if err != nil {
+				t.Fatalf("could not create processor: %v", err)
+			}
+
+			got, err := processor.ProcessFiles([]string{"bundle.tar", "bundle.zip"})
+			assert.Equal(t, tt.want, got)
+			if !tt.keepGoing {
+				assert.ErrorIs(t, err, contracts.ErrArchiveMemberTooLarge)
+				return
+			}
+			var failures contracts.FilesErrors
+			if !errors.As(err, &failures) {
+				t.Fatalf("expected FilesErrors, got %v", err)
+			}
+			assert.Len(t, failures, len(tt.wantFails))
+			for _, name := range tt.wantFails {
+				assert.ErrorIs(t, failures[name], contracts.ErrArchiveMemberTooLarge, name)
+			}
+		})
+	}
+}
+
+func Test_cappedReader(t *testing.T) {
+	content, err := io.ReadAll(newCappedReader(strings.NewReader("int a;"), 6))
+	assert.NoError(t, err)
+	assert.Equal(t, "int a;", string(content))
+
+	_, err = io.ReadAll(newCappedReader(strings.NewReader("int main;"), 6))
+	assert.ErrorIs(t, err, contracts.ErrArchiveMemberTooLarge)
+}
+
+func Test_ProcessFiles_symlinks(t *testing.T) {
+	root := t.TempDir()
+	setup := []func() error{
+		func() error { return os.MkdirAll(filepath.Join(root, "sub", "deep"), 0o755) },
+		func() error { return os.WriteFile(filepath.Join(root, "file.c"), []byte("int main;"), 0o644) },
+		func() error {
+			return os.WriteFile(filepath.Join(root, "sub", "deep", "file2.c"), []byte("int other;"), 0o644)
+		},
+		func() error { return os.Symlink("file.c", filepath.Join(root, "link.c")) },
+		func() error { return os.Symlink("deep", filepath.Join(root, "sub", "linkdir")) },
+		func() error { return os.Symlink("..", filepath.Join(root, "sub", "deep", "loop")) },
+		func() error { return os.Symlink(root, filepath.Join(root, "sub", "absloop")) },
+	}
+	for _, step := range setup {
+		if err := step(); err != nil {
+			t.Skipf("could not create symlinks: %v", err)
+		}
+	}
+	broken := filepath.Join(root, "broken.c")
+	if err := os.Symlink("missing.c", broken); err != nil {
+		t.Fatalf("could not create broken symlink: %v", err)
+	}
+
+	echoProcessor := func(filepath string, content io.Reader) (string, error) {
+		return filepath, nil
+	}
+	rel := func(name string) string {
+		return filepath.Join(root, name)
+	}
+
+	tests := []struct {
+		name      string
+		config    contracts.FilesProcessorConfig[string]
+		inputs    []string
+		want      map[string]string
+		wantErr   bool
+		wantFails []string
+	}{
+		{
+			name: "ignores symlinks when skipping them",
+			config: contracts.FilesProcessorConfig[string]{
+				Processor:    echoProcessor,
+				Recursive:    true,
+				SkipSymlinks: true,
+			},
+			inputs: []string{root},
+			want: map[string]string{
+				rel("file.c"):           rel("file.c"),
+				rel("sub/deep/file2.c"): rel("sub/deep/file2.c"),
+			},
+		},
+		{
+			name: "follows explicit symlinks when skipping them",
+			config: contracts.FilesProcessorConfig[string]{
+				Processor:    echoProcessor,
+				Recursive:    true,
+				SkipSymlinks: true,
+			},
+			inputs: []string{rel("link.c"), rel("sub/linkdir")},
+			want: map[string]string{
+				rel("link.c"):              rel("link.c"),
+				rel("sub/linkdir/file2.c"): rel("sub/linkdir/file2.c"),
+			},
+		},
+		{
+			name: "follows symlinks by default and skips loops",
+			config: contracts.FilesProcessorConfig[string]{
+				Processor:            echoProcessor,
+				Recursive:            true,
+				FilesExcludePatterns: []string{"broken"},
+			},
+			inputs: []string{rel("sub")},
+			want: map[string]string{
+				rel("sub/deep/file2.c"):    rel("sub/deep/file2.c"),
+				rel("sub/linkdir/file2.c"): rel("sub/linkdir/file2.c"),
+				rel("sub/absloop/file.c"):  rel("sub/absloop/file.c"),
+				rel("sub/absloop/link.c"):  rel("sub/absloop/link.c"),
+			},
+		},
+		{
+			name: "fails with broken symlinks when following them",
+			config: contracts.FilesProcessorConfig[string]{
+				Processor: echoProcessor,
+				Recursive: true,
+			},
+			inputs:  []string{root},
+			wantErr: true,
+		},
+		{
+			name: "reports broken symlinks when following them",
+			config: contracts.FilesProcessorConfig[string]{
+				Processor: echoProcessor,
+				KeepGoing: true,
+			},
+			inputs: []string{rel("file.c"), broken},
+			want: map[string]string{
+				rel("file.c"): rel("file.c"),
+			},
+			wantErr:   true,
+			wantFails: []string{broken},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor, err := New(logrus.New(), tt.config)
+			if err != nil {
+				t.Fatalf("could not create processor: %v", err)
+			}
+
+			got, err := processor.ProcessFiles(tt.inputs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			for _, fail := range tt.wantFails {
+				var failures contracts.FilesErrors
+				if !errors.As(err, &failures) {
+					t.Fatalf("expected FilesErrors, got %v", err)
+				}
+				assert.ErrorIs(t, failures[fail], contracts.ErrBrokenSymlink)
+			}
+		})
+	}
+}
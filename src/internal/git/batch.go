@@ -0,0 +1,110 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// catFile reads objects through a single `git cat-file --batch` process, started on first use, instead of one
+// process per object
+type catFile struct {
+	runner *runner
+	mutex  sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+func (me *catFile) start() error {
+	me.runner.logger.Debugf("running git cat-file --batch")
+	cmd := exec.Command("git", "cat-file", "--batch")
+	cmd.Dir = me.runner.directory
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	err = cmd.Start()
+	if err != nil {
+		return fmt.Errorf("git cat-file: %w", err)
+	}
+	me.cmd = cmd
+	me.stdin = stdin
+	me.stdout = bufio.NewReader(stdout)
+	return nil
+}
+
+// read returns the content of a blob
+func (me *catFile) read(object string) ([]byte, error) {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if me.cmd == nil {
+		err := me.start()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	content, err := me.request(object)
+	if err != nil {
+		// the stream cannot be trusted anymore, the next read starts a new process
+		me.stop()
+	}
+	return content, err
+}
+
+func (me *catFile) request(object string) ([]byte, error) {
+	_, err := fmt.Fprintf(me.stdin, "%s\n", object)
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file: %w", err)
+	}
+	header, err := me.stdout.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file: %w", err)
+	}
+	fields := strings.Fields(header)
+	if len(fields) == 2 && fields[1] == "missing" {
+		return nil, fmt.Errorf("git cat-file: missing object %s", object)
+	}
+	if len(fields) != 3 || fields[1] != "blob" {
+		return nil, fmt.Errorf("git cat-file: unexpected header %q for %s", strings.TrimSpace(header), object)
+	}
+	size, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file: invalid size for %s: %w", object, err)
+	}
+	// content is followed by a newline
+	content := make([]byte, size+1)
+	_, err = io.ReadFull(me.stdout, content)
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file: %w", err)
+	}
+	return content[:size], nil
+}
+
+func (me *catFile) stop() error {
+	if me.cmd == nil {
+		return nil
+	}
+	me.stdin.Close()
+	err := me.cmd.Wait()
+	me.cmd = nil
+	me.stdin = nil
+	me.stdout = nil
+	return err
+}
+
+func (me *catFile) close() error {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	return me.stop()
+}
@@ -0,0 +1,113 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+)
+
+// uncommittedHash is used by git blame for lines which are not committed yet
+const uncommittedHash = "0000000000000000000000000000000000000000"
+
+type blamer struct {
+	runner
+	revision string
+}
+
+func NewBlamer(logger *logrus.Logger, config contracts.GitBlamerConfig) (contracts.GitBlamer, error) {
+	return &blamer{
+		runner: runner{
+			logger:    logger,
+			directory: config.Directory,
+		},
+		revision: config.Revision,
+	}, nil
+}
+
+func (me *blamer) Blame(filepath string) (map[uint]contracts.Blame, error) {
+	args := []string{"blame", "--porcelain"}
+	if me.revision != "" {
+		args = append(args, me.revision)
+	}
+	out, err := me.run(append(args, "--", filepath)...)
+	if err != nil {
+		return nil, err
+	}
+	return parsePorcelain(out)
+}
+
+// parsePorcelain reads the output of `git blame --porcelain`, where the details of a commit are only given the first
+// time it appears
+func parsePorcelain(out []byte) (map[uint]contracts.Blame, error) {
+	commits := map[string]*contracts.Blame{}
+	lines := map[uint]contracts.Blame{}
+
+	var current *contracts.Blame
+	var hash string
+	var lineNumber uint
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "\t") {
+			if current == nil {
+				return nil, fmt.Errorf("unexpected content line in blame output")
+			}
+			if hash != uncommittedHash {
+				lines[lineNumber] = *current
+			}
+			current = nil
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		if current == nil {
+			fields := strings.Fields(value)
+			if len(key) != len(uncommittedHash) || len(fields) < 2 {
+				return nil, fmt.Errorf("invalid blame header %q", line)
+			}
+			final, err := strconv.ParseUint(fields[1], 10, 0)
+			if err != nil {
+				return nil, fmt.Errorf("invalid blame header %q: %w", line, err)
+			}
+			hash = key
+			lineNumber = uint(final)
+			current = commits[hash]
+			if current == nil {
+				current = &contracts.Blame{Commit: hash}
+				commits[hash] = current
+			}
+			continue
+		}
+
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-mail":
+			current.AuthorEmail = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+		case "author-time":
+			timestamp, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid author time %q: %w", value, err)
+			}
+			current.Date = time.Unix(timestamp, 0)
+		case "author-tz":
+			zone, err := time.Parse("-0700", value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid author timezone %q: %w", value, err)
+			}
+			_, offset := zone.Zone()
+			current.Date = current.Date.In(time.FixedZone(value, offset))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
@@ -0,0 +1,143 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parsePorcelain(t *testing.T) {
+	first := "1111111111111111111111111111111111111111"
+	second := "2222222222222222222222222222222222222222"
+	out := strings.Join([]string{
+		first + " 1 1 2",
+		"author Alice",
+		"author-mail <alice@example.com>",
+		"author-time 1672837200",
+		"author-tz +0100",
+		"committer Alice",
+		"summary first",
+		"filename main.c",
+		"\tint main() {",
+		first + " 2 2",
+		"\t  // TODO: later",
+		second + " 3 3 1",
+		"author Bob",
+		"author-mail <bob@example.com>",
+		"author-time 1672923600",
+		"author-tz -0500",
+		"summary second",
+		"previous " + first + " main.c",
+		"filename main.c",
+		"\t  return 0;",
+		uncommittedHash + " 4 4 1",
+		"author Not Committed Yet",
+		"author-mail <not.committed.yet>",
+		"author-time 1672923600",
+		"author-tz +0000",
+		"filename main.c",
+		"\t}",
+		"",
+	}, "\n")
+
+	alice := contracts.Blame{
+		Commit:      first,
+		Author:      "Alice",
+		AuthorEmail: "alice@example.com",
+		Date:        time.Unix(1672837200, 0).In(time.FixedZone("+0100", 3600)),
+	}
+	got, err := parsePorcelain([]byte(out))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[uint]contracts.Blame{
+		1: alice,
+		2: alice,
+		3: {
+			Commit:      second,
+			Author:      "Bob",
+			AuthorEmail: "bob@example.com",
+			Date:        time.Unix(1672923600, 0).In(time.FixedZone("-0500", -5*3600)),
+		},
+	}, got)
+	assert.Equal(t, "2023-01-04", got[1].Date.Format("2006-01-02"))
+
+	_, err = parsePorcelain([]byte("\tcontent\n"))
+	assert.Error(t, err)
+	_, err = parsePorcelain([]byte("not a header\n"))
+	assert.Error(t, err)
+}
+
+func Test_Blame(t *testing.T) {
+	dir := setupRepository(t)
+	revParse := func(revision string) string {
+		out, err := exec.Command("git", "-C", dir, "rev-parse", revision).Output()
+		if err != nil {
+			t.Fatalf("git rev-parse failed: %v", err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	tests := []struct {
+		name     string
+		config   contracts.GitBlamerConfig
+		file     string
+		wantLine string
+		wantErr  bool
+	}{
+		{
+			name:     "works",
+			config:   contracts.GitBlamerConfig{Directory: dir},
+			file:     "sub/file2.c",
+			wantLine: revParse("v1"),
+		},
+		{
+			name:   "skips uncommitted lines",
+			config: contracts.GitBlamerConfig{Directory: dir},
+			file:   "file.c",
+		},
+		{
+			name:     "works with a revision",
+			config:   contracts.GitBlamerConfig{Directory: dir, Revision: "HEAD"},
+			file:     "file.c",
+			wantLine: revParse("HEAD"),
+		},
+		{
+			name:    "fails with unknown files",
+			config:  contracts.GitBlamerConfig{Directory: dir},
+			file:    "missing.c",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			me, err := NewBlamer(logrus.New(), tt.config)
+			if err != nil {
+				t.Fatalf("failed to create blamer: %v", err)
+			}
+
+			got, err := me.Blame(tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantLine == "" {
+				assert.Empty(t, got)
+				return
+			}
+			if assert.Contains(t, got, uint(1)) {
+				assert.Equal(t, tt.wantLine, got[1].Commit)
+				assert.Equal(t, "test", got[1].Author)
+				assert.Equal(t, "test@example.com", got[1].AuthorEmail)
+				assert.False(t, got[1].Date.IsZero())
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	modeDirectory = "040000"
+	modeSymlink   = "120000"
+	modeSubmodule = "160000"
+)
+
+type entry struct {
+	name     string
+	mode     fs.FileMode
+	size     int64
+	object   string
+	modTime  time.Time
+	children []*entry
+}
+
+func (me *entry) Name() string               { return me.name }
+func (me *entry) Size() int64                { return me.size }
+func (me *entry) Mode() fs.FileMode          { return me.mode }
+func (me *entry) ModTime() time.Time         { return me.modTime }
+func (me *entry) IsDir() bool                { return me.mode.IsDir() }
+func (me *entry) Sys() any                   { return nil }
+func (me *entry) Type() fs.FileMode          { return me.mode.Type() }
+func (me *entry) Info() (fs.FileInfo, error) { return me, nil }
+
+type gitFS struct {
+	runner
+	entries map[string]*entry
+	blobs   *catFile
+}
+
+func NewFS(logger *logrus.Logger, config contracts.GitFSConfig) (fs.FS, error) {
+	me := &gitFS{
+		runner: runner{
+			logger:    logger,
+			directory: config.Directory,
+		},
+		entries: map[string]*entry{},
+	}
+	me.blobs = &catFile{runner: &me.runner}
+
+	out, err := me.run("rev-parse", "--verify", "--quiet", config.Revision+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown revision %q: %w", config.Revision, err)
+	}
+	commit := strings.TrimSpace(string(out))
+
+	out, err = me.run("rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.TrimSpace(string(out))
+
+	out, err = me.run("show", "--no-patch", "--format=%ct", commit)
+	if err != nil {
+		return nil, err
+	}
+	timestamp, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commit time for %s: %w", commit, err)
+	}
+	modTime := time.Unix(timestamp, 0)
+
+	out, err = me.run("ls-tree", "--full-tree", "-r", "-t", "-l", "-z", fmt.Sprintf("%s:%s", commit, prefix))
+	if err != nil {
+		return nil, err
+	}
+
+	me.entries["."] = &entry{
+		name:    ".",
+		mode:    fs.ModeDir | 0o555,
+		modTime: modTime,
+	}
+	for _, line := range strings.Split(string(out), "\x00") {
+		if line == "" {
+			continue
+		}
+		err := me.add(line, modTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", config.Revision, err)
+		}
+	}
+	logger.Infof("loaded %d entries from revision %s (%s)", len(me.entries), config.Revision, commit)
+	return me, nil
+}
+
+func (me *gitFS) add(line string, modTime time.Time) error {
+	meta, name, found := strings.Cut(line, "\t")
+	if !found {
+		return fmt.Errorf("malformed entry %q", line)
+	}
+	fields := strings.Fields(meta)
+	if len(fields) != 4 {
+		return fmt.Errorf("malformed entry %q", line)
+	}
+
+	current := &entry{
+		name:    path.Base(name),
+		object:  fields[2],
+		modTime: modTime,
+	}
+	switch fields[0] {
+	case modeDirectory:
+		current.mode = fs.ModeDir | 0o555
+	case modeSymlink, modeSubmodule:
+		me.logger.Debugf("skipping %s (mode %s)", name, fields[0])
+		return nil
+	default:
+		size, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid size for %s: %w", name, err)
+		}
+		current.size = size
+		current.mode = 0o444
+		if fields[0] == "100755" {
+			current.mode = 0o555
+		}
+	}
+
+	parent, found := me.entries[path.Dir(name)]
+	if !found {
+		return fmt.Errorf("missing parent directory for %s", name)
+	}
+	parent.children = append(parent.children, current)
+	me.entries[name] = current
+	return nil
+}
+
+func (me *gitFS) lookup(op, name string) (*entry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	found, ok := me.entries[name]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return found, nil
+}
+
+func (me *gitFS) Open(name string) (fs.File, error) {
+	found, err := me.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if found.IsDir() {
+		return &directory{entry: found}, nil
+	}
+	content, err := me.blobs.read(found.object)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &file{entry: found, Reader: bytes.NewReader(content)}, nil
+}
+
+// Close stops the git process used to read files, it is started again when needed
+func (me *gitFS) Close() error {
+	return me.blobs.close()
+}
+
+func (me *gitFS) Stat(name string) (fs.FileInfo, error) {
+	return me.lookup("stat", name)
+}
+
+func (me *gitFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	found, err := me.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !found.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fmt.Errorf("not a directory")}
+	}
+	return (&directory{entry: found}).ReadDir(-1)
+}
+
+type file struct {
+	*entry
+	*bytes.Reader
+}
+
+func (me *file) Stat() (fs.FileInfo, error) { return me.entry, nil }
+func (me *file) Close() error               { return nil }
+
+type directory struct {
+	*entry
+	offset int
+}
+
+func (me *directory) Stat() (fs.FileInfo, error) { return me.entry, nil }
+func (me *directory) Close() error               { return nil }
+
+func (me *directory) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: me.name, Err: fmt.Errorf("is a directory")}
+}
+
+func (me *directory) ReadDir(count int) ([]fs.DirEntry, error) {
+	children := make([]*entry, len(me.children))
+	copy(children, me.children)
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].name < children[j].name
+	})
+	children = children[me.offset:]
+
+	if count > 0 && len(children) == 0 {
+		return nil, io.EOF
+	}
+	if count > 0 && count < len(children) {
+		children = children[:count]
+	}
+	me.offset += len(children)
+
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, child := range children {
+		entries = append(entries, child)
+	}
+	return entries, nil
+}
@@ -0,0 +1,163 @@
+package git
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRepository(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+		}
+		if err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+	}
+
+	git("init", "--quiet")
+	write("file.c", "committed\n")
+	write("sub/file2.c", "nested\n")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "first")
+	git("tag", "v1")
+	write("file.c", "changed\n")
+	write("sub/file3.c", "new\n")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "second")
+	write("file.c", "uncommitted\n")
+	return dir
+}
+
+func Test_NewFS(t *testing.T) {
+	dir := setupRepository(t)
+
+	tests := []struct {
+		name      string
+		config    contracts.GitFSConfig
+		wantFiles map[string]string
+		wantErr   bool
+	}{
+		{
+			name: "works with a tag",
+			config: contracts.GitFSConfig{
+				Directory: dir,
+				Revision:  "v1",
+			},
+			wantFiles: map[string]string{
+				"file.c":      "committed\n",
+				"sub/file2.c": "nested\n",
+			},
+		},
+		{
+			name: "works with HEAD",
+			config: contracts.GitFSConfig{
+				Directory: dir,
+				Revision:  "HEAD",
+			},
+			wantFiles: map[string]string{
+				"file.c":      "changed\n",
+				"sub/file2.c": "nested\n",
+				"sub/file3.c": "new\n",
+			},
+		},
+		{
+			name: "works from a sub-directory",
+			config: contracts.GitFSConfig{
+				Directory: filepath.Join(dir, "sub"),
+				Revision:  "HEAD~1",
+			},
+			wantFiles: map[string]string{
+				"file2.c": "nested\n",
+			},
+		},
+		{
+			name: "fails with an unknown revision",
+			config: contracts.GitFSConfig{
+				Directory: dir,
+				Revision:  "v2",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys, err := NewFS(logrus.New(), tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := map[string]string{}
+			for name := range tt.wantFiles {
+				content, err := fs.ReadFile(fsys, name)
+				if err != nil {
+					t.Fatalf("could not read %s: %v", name, err)
+				}
+				got[name] = string(content)
+			}
+			assert.Equal(t, tt.wantFiles, got)
+
+			expected := make([]string, 0, len(tt.wantFiles))
+			for name := range tt.wantFiles {
+				expected = append(expected, name)
+			}
+			err = fstest.TestFS(fsys, expected...)
+			if err != nil {
+				t.Errorf("invalid filesystem: %v", err)
+			}
+		})
+	}
+}
+
+func Test_FS_Close(t *testing.T) {
+	dir := setupRepository(t)
+
+	fsys, err := NewFS(logrus.New(), contracts.GitFSConfig{
+		Directory: dir,
+		Revision:  "HEAD",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, name := range []string{"file.c", "sub/file2.c", "file.c"} {
+		_, err := fs.ReadFile(fsys, name)
+		assert.NoError(t, err)
+	}
+	closer, ok := fsys.(io.Closer)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.NoError(t, closer.Close())
+
+	content, err := fs.ReadFile(fsys, "sub/file3.c")
+	assert.NoError(t, err)
+	assert.Equal(t, "new\n", string(content))
+	assert.NoError(t, closer.Close())
+}
@@ -0,0 +1,32 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+type runner struct {
+	logger    *logrus.Logger
+	directory string
+}
+
+func (me *runner) run(args ...string) ([]byte, error) {
+	me.logger.Debugf("running git %s", strings.Join(args, " "))
+	cmd := exec.Command("git", args...)
+	cmd.Dir = me.directory
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return nil, fmt.Errorf("git %s: %w", args[0], err)
+		}
+		return nil, fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return out, nil
+}
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/LouisBrunner/gofixit/src/contracts"
 	"github.com/LouisBrunner/gofixit/src/utils"
@@ -15,54 +16,134 @@ const maxPreAllocated = 100
 
 type parserImpl struct {
 	contracts.ParsingConfig
-	re     regexp.Regexp
-	order  ordering
-	logger *logrus.Logger
+	re        regexp.Regexp
+	groups    groups
+	directive regexp.Regexp
+	renderer  renderer
+	logger    *logrus.Logger
 }
 
 func New(logger *logrus.Logger, config contracts.ParsingConfig) (contracts.Parser, error) {
-	re, order, err := buildRE(logger, config)
+	tmpl, err := parseTemplate(config)
 	if err != nil {
 		return nil, fmt.Errorf("cannot build internal matcher: %w", err)
 	}
+	re, groups, err := buildRE(logger, config, tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build internal matcher: %w", err)
+	}
+	directive, err := buildDirectiveRE(logger, config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build internal matcher: %w", err)
+	}
+	renderer, err := newRenderer(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build internal renderer: %w", err)
+	}
 
 	return &parserImpl{
 		ParsingConfig: config,
 		re:            *re,
-		order:         *order,
+		groups:        *groups,
+		directive:     *directive,
+		renderer:      *renderer,
 		logger:        logger,
 	}, nil
 }
 
+// parseMetadata reads `;key=value` pairs, returning nil when there are none
+func parseMetadata(meta string) map[string]string {
+	if meta == "" {
+		return nil
+	}
+	metadata := map[string]string{}
+	for _, pair := range strings.Split(strings.TrimPrefix(meta, ";"), ";") {
+		key, value, _ := strings.Cut(pair, "=")
+		metadata[key] = value
+	}
+	return metadata
+}
+
+func formatMetadata(metadata map[string]string) string {
+	builder := &strings.Builder{}
+	for _, entry := range utils.SortedMap(metadata) {
+		fmt.Fprintf(builder, ";%s=%s", entry.Key, entry.Value)
+	}
+	return builder.String()
+}
+
+func submatch(line string, indices []int, idx int) string {
+	if idx < 0 || indices[2*idx] < 0 {
+		return ""
+	}
+	return line[indices[2*idx]:indices[2*idx+1]]
+}
+
 func (me *parserImpl) Parse(fileContent string) ([]contracts.ParsedComment, error) {
 	lines := strings.Split(fileContent, "\n")
 
 	results := make([]contracts.ParsedComment, 0, utils.Min(len(lines)/10, maxPreAllocated))
 	for num, line := range lines {
 		me.logger.Debugf("parsing line %q", line)
-		matches := me.re.FindStringSubmatch(line)
-		me.logger.Debugf("found matches %+v", matches)
-		if len(matches) < expectedMatches {
+		indices := me.re.FindStringSubmatchIndex(line)
+		me.logger.Debugf("found matches %+v", indices)
+		if indices == nil {
 			continue
 		}
-		me.logger.Infof("line matched %+v", matches)
+		group := func(idx int) string {
+			return submatch(line, indices, idx)
+		}
+		me.logger.Infof("line matched %q", group(me.groups.everything))
 		var expiry *time.Time
-		if matches[me.order.matchExpiry] != "" {
-			expiryValue, err := time.Parse(me.DateLayout, matches[me.order.matchExpiry])
+		invalidExpiry := ""
+		if group(me.groups.expiry) != "" {
+			expiryValue, err := time.Parse(me.DateLayout, group(me.groups.expiry))
 			if err != nil {
-				me.logger.Errorf("invalid date layout %q: %v", matches[me.order.matchExpiry], err)
-				continue
+				me.logger.Warnf("invalid date layout %q: %v", group(me.groups.expiry), err)
+				invalidExpiry = group(me.groups.expiry)
+			} else {
+				expiry = &expiryValue
 			}
-			expiry = &expiryValue
 		}
 		results = append(results, contracts.ParsedComment{
-			CommentPrefix: matches[me.order.matchComment],
-			Prefix:        matches[me.order.matchPrefix],
-			Content:       matches[me.order.matchContent],
+			CommentPrefix: group(me.groups.comment),
+			Prefix:        group(me.groups.prefix),
+			Owner:         strings.TrimSpace(group(me.groups.owner)),
+			Content:       group(me.groups.content),
 			Expiry:        expiry,
+			InvalidExpiry: invalidExpiry,
+			Metadata:      parseMetadata(group(me.groups.meta)),
 			LineNumber:    uint(num) + 1,
-			OriginalLine:  matches[me.order.matchEverything],
+			Column:        uint(utf8.RuneCountInString(line[:indices[2*me.groups.everything]])) + 1,
+			OriginalLine:  group(me.groups.everything),
+			SourceLine:    line,
 		})
 	}
 	return results, nil
 }
+
+func (me *parserImpl) Rewrite(line string, expiry time.Time, metadata map[string]string) (string, error) {
+	indices := me.re.FindStringSubmatchIndex(line)
+	if indices == nil {
+		return "", fmt.Errorf("no comment found in %q", line)
+	}
+
+	date := expiry.Format(me.DateLayout)
+	tag, err := me.renderer.render(
+		submatch(line, indices, me.groups.prefix),
+		submatch(line, indices, me.groups.owner),
+		date,
+		formatMetadata(metadata),
+	)
+	if err != nil {
+		return "", err
+	}
+	rewritten := line[:indices[2*me.groups.tag]] + tag + line[indices[2*me.groups.tag+1]:]
+
+	// the expiry pattern can be arbitrarily complex, make sure the result reads back as intended
+	check := me.re.FindStringSubmatchIndex(rewritten)
+	if check == nil || submatch(rewritten, check, me.groups.expiry) != date {
+		return "", fmt.Errorf("rendered comment %q does not match the expiry pattern", rewritten)
+	}
+	return rewritten, nil
+}
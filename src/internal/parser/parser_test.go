@@ -1,3 +1,5 @@
+// gofixit:ignore-file -- the comments used as fixtures are overdue on purpose
+
 package parser
 
 import (
@@ -14,7 +16,7 @@ func Test_New(t *testing.T) {
 	tests := []struct {
 		name    string
 		config  contracts.ParsingConfig
-		wantErr bool
+		wantErr string
 	}{
 		{
 			name: "fails, template has no .Prefix",
@@ -25,7 +27,7 @@ func Test_New(t *testing.T) {
 				DateLayout:      "02/01/2006",
 				CaseSensitive:   true,
 			},
-			wantErr: true,
+			wantErr: "expiry template must contain {{.Prefix}}",
 		},
 		{
 			name: "fails, template has no .Date",
@@ -36,7 +38,7 @@ func Test_New(t *testing.T) {
 				DateLayout:      "02/01/2006",
 				CaseSensitive:   true,
 			},
-			wantErr: true,
+			wantErr: "expiry template must contain {{.Date}}",
 		},
 		{
 			name: "template is invalid",
@@ -47,7 +49,7 @@ func Test_New(t *testing.T) {
 				DateLayout:      "02/01/2006",
 				CaseSensitive:   true,
 			},
-			wantErr: true,
+			wantErr: "bad character",
 		},
 		{
 			name: "date layout is unsupported",
@@ -58,7 +60,29 @@ func Test_New(t *testing.T) {
 				DateLayout:      "02/Jan/2006",
 				CaseSensitive:   true,
 			},
-			wantErr: true,
+			wantErr: "unsupported character",
+		},
+		{
+			name: "fails, template uses .Prefix twice",
+			config: contracts.ParsingConfig{
+				CommentPrefixes: []string{"//"},
+				Prefixes:        []string{"TODO"},
+				ExpiryPattern:   "{{.Prefix}}(?:->{{.Date}})?(?:{{.Prefix}})?:",
+				DateLayout:      "02/01/2006",
+				CaseSensitive:   true,
+			},
+			wantErr: "expiry template can only contain {{.Prefix}} once",
+		},
+		{
+			name: "fails, template uses .Date twice",
+			config: contracts.ParsingConfig{
+				CommentPrefixes: []string{"//"},
+				Prefixes:        []string{"TODO"},
+				ExpiryPattern:   "{{.Prefix}}(?:->{{.Date}}|\\[{{.Date}}\\])?:",
+				DateLayout:      "02/01/2006",
+				CaseSensitive:   true,
+			},
+			wantErr: "expiry template can only contain {{.Date}} once",
 		},
 		{
 			name: "works",
@@ -74,9 +98,13 @@ func Test_New(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			_, err := New(logrus.New(), tt.config)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			if tt.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tt.wantErr)
+				}
+				return
 			}
+			assert.NoError(t, err)
 		})
 	}
 }
@@ -122,7 +150,9 @@ do {
 					Prefix:        "fixit",
 					Content:       "made with love",
 					LineNumber:    4,
+					Column:        1,
 					OriginalLine:  "@fixit: made with love",
+					SourceLine:    "@fixit: made with love",
 				},
 				{
 					CommentPrefix: "@",
@@ -130,28 +160,36 @@ do {
 					Content:       "better condition?",
 					Expiry:        utils.Pointerize(utils.Must(time.Parse("02/01/2006", "30/04/1993"))),
 					LineNumber:    7,
+					Column:        14,
 					OriginalLine:  "@fixit->30/04/1993: better condition?",
+					SourceLine:    "} while (1); @fixit->30/04/1993: better condition?",
 				},
 				{
 					CommentPrefix: "@",
 					Prefix:        "later",
 					Content:       "don't match me",
 					LineNumber:    9,
+					Column:        1,
 					OriginalLine:  "@ later: don't match me",
+					SourceLine:    "@ later: don't match me",
 				},
 				{
 					CommentPrefix: "@",
 					Prefix:        "later",
 					Content:       "finish when I have time",
 					LineNumber:    13,
+					Column:        1,
 					OriginalLine:  "@later: finish when I have time",
+					SourceLine:    "@later: finish when I have time",
 				},
 				{
 					CommentPrefix: "%",
 					Prefix:        "later",
 					Content:       "bit too busy right now",
 					LineNumber:    14,
+					Column:        1,
 					OriginalLine:  "%later: bit too busy right now",
+					SourceLine:    "%later: bit too busy right now",
 				},
 			},
 		},
@@ -171,12 +209,24 @@ do {
 %later: maybe after dinner
 `,
 			want: []contracts.ParsedComment{
+				{
+					CommentPrefix: "@",
+					Prefix:        "fixit",
+					Content:       "forgot to implement",
+					InvalidExpiry: "99/04/1993",
+					LineNumber:    4,
+					Column:        1,
+					OriginalLine:  "@fixit->99/04/1993: forgot to implement",
+					SourceLine:    "@fixit->99/04/1993: forgot to implement",
+				},
 				{
 					CommentPrefix: "%",
 					Prefix:        "later",
 					Content:       "maybe after dinner",
 					LineNumber:    5,
+					Column:        1,
 					OriginalLine:  "%later: maybe after dinner",
+					SourceLine:    "%later: maybe after dinner",
 				},
 			},
 		},
@@ -202,14 +252,92 @@ do {
 					Content:       "forgot to implement",
 					Expiry:        utils.Pointerize(utils.Must(time.Parse("02/01/2006", "18/05/1991"))),
 					LineNumber:    4,
+					Column:        1,
 					OriginalLine:  "@18/05/1991.fixit forgot to implement",
+					SourceLine:    "@18/05/1991.fixit forgot to implement",
 				},
 				{
 					CommentPrefix: "%",
 					Prefix:        "later",
 					Content:       "maybe after dinner",
 					LineNumber:    5,
+					Column:        1,
 					OriginalLine:  "%.later maybe after dinner",
+					SourceLine:    "%.later maybe after dinner",
+				},
+			},
+		},
+		{
+			name: "works, with owner",
+			config: contracts.ParsingConfig{
+				CommentPrefixes: []string{"@", "%"},
+				Prefixes:        []string{"later"},
+				ExpiryPattern:   "{{.Prefix}}(?:\\({{.Owner}}\\))?(?:\\[{{.Date}}\\])?:",
+				DateLayout:      "2006-01-02",
+				CaseSensitive:   true,
+			},
+			fileContent: `
+	x := 5 @later(alice)[2022-06-15]: remove hack
+% later( bob ): no date
+%later[2022-06-15]: no owner
+`,
+			want: []contracts.ParsedComment{
+				{
+					CommentPrefix: "@",
+					Prefix:        "later",
+					Owner:         "alice",
+					Content:       "remove hack",
+					Expiry:        utils.Pointerize(utils.Must(time.Parse("2006-01-02", "2022-06-15"))),
+					LineNumber:    2,
+					Column:        9,
+					OriginalLine:  "@later(alice)[2022-06-15]: remove hack",
+					SourceLine:    "\tx := 5 @later(alice)[2022-06-15]: remove hack",
+				},
+				{
+					CommentPrefix: "%",
+					Prefix:        "later",
+					Owner:         "bob",
+					Content:       "no date",
+					LineNumber:    3,
+					Column:        1,
+					OriginalLine:  "% later( bob ): no date",
+					SourceLine:    "% later( bob ): no date",
+				},
+				{
+					CommentPrefix: "%",
+					Prefix:        "later",
+					Content:       "no owner",
+					Expiry:        utils.Pointerize(utils.Must(time.Parse("2006-01-02", "2022-06-15"))),
+					LineNumber:    4,
+					Column:        1,
+					OriginalLine:  "%later[2022-06-15]: no owner",
+					SourceLine:    "%later[2022-06-15]: no owner",
+				},
+			},
+		},
+		{
+			name: "works, with metadata",
+			config: contracts.ParsingConfig{
+				CommentPrefixes: []string{"@"},
+				Prefixes:        []string{"later"},
+				ExpiryPattern:   "{{.Prefix}}(?:\\[{{.Date}}{{.Meta}}\\])?:",
+				DateLayout:      "2006-01-02",
+				CaseSensitive:   true,
+			},
+			fileContent: `
+@later[2022-06-15;snoozed=2;by=]: pushed back
+`,
+			want: []contracts.ParsedComment{
+				{
+					CommentPrefix: "@",
+					Prefix:        "later",
+					Content:       "pushed back",
+					Expiry:        utils.Pointerize(utils.Must(time.Parse("2006-01-02", "2022-06-15"))),
+					Metadata:      map[string]string{"snoozed": "2", "by": ""},
+					LineNumber:    2,
+					Column:        1,
+					OriginalLine:  "@later[2022-06-15;snoozed=2;by=]: pushed back",
+					SourceLine:    "@later[2022-06-15;snoozed=2;by=]: pushed back",
 				},
 			},
 		},
@@ -239,28 +367,36 @@ do {
 					Content:       "forgot to implement",
 					Expiry:        utils.Pointerize(utils.Must(time.Parse("02/01/2006", "18/05/1991"))),
 					LineNumber:    4,
+					Column:        1,
 					OriginalLine:  "@FixIt->18/05/1991: forgot to implement",
+					SourceLine:    "@FixIt->18/05/1991: forgot to implement",
 				},
 				{
 					CommentPrefix: "%",
 					Prefix:        "LATER",
 					Content:       "maybe after dinner",
 					LineNumber:    5,
+					Column:        1,
 					OriginalLine:  "%LATER: maybe after dinner",
+					SourceLine:    "%LATER: maybe after dinner",
 				},
 				{
 					CommentPrefix: "@",
 					Prefix:        "later",
 					Content:       "MORE",
 					LineNumber:    7,
+					Column:        1,
 					OriginalLine:  "@later: MORE",
+					SourceLine:    "@later: MORE",
 				},
 				{
 					CommentPrefix: "%",
 					Prefix:        "FiXiT",
 					Content:       "EVEN MORE",
 					LineNumber:    10,
+					Column:        1,
 					OriginalLine:  "%FiXiT: EVEN MORE",
+					SourceLine:    "%FiXiT: EVEN MORE",
 				},
 			},
 		},
@@ -280,3 +416,262 @@ do {
 		})
 	}
 }
+
+func Test_Rewrite(t *testing.T) {
+	expiry := utils.Must(time.Parse("2006-01-02", "2022-09-17"))
+	defaultConfig := contracts.ParsingConfig{
+		CommentPrefixes: []string{"//", "#"},
+		Prefixes:        []string{"TODO", "FIXME"},
+		ExpiryPattern:   "{{.Prefix}}(?:\\({{.Owner}}\\))?(?:\\[{{.Date}}{{.Meta}}\\])?",
+		DateLayout:      "2006-01-02",
+		CaseSensitive:   true,
+	}
+
+	tests := []struct {
+		name     string
+		config   contracts.ParsingConfig
+		line     string
+		metadata map[string]string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:   "works, undated",
+			config: defaultConfig,
+			line:   "\tfoo() // TODO: implement",
+			want:   "\tfoo() // TODO[2022-09-17]: implement",
+		},
+		{
+			name:   "works, with owner",
+			config: defaultConfig,
+			line:   "# FIXME(alice) broken",
+			want:   "# FIXME(alice)[2022-09-17] broken",
+		},
+		{
+			name:   "works, replacing a date",
+			config: defaultConfig,
+			line:   "// TODO(bob)[2022-06-15]: implement\r",
+			want:   "// TODO(bob)[2022-09-17]: implement\r",
+		},
+		{
+			name:     "works, adding metadata",
+			config:   defaultConfig,
+			line:     "// TODO[2022-06-15;snoozed=1;by=alice]: implement",
+			metadata: map[string]string{"snoozed": "2", "by": "alice"},
+			want:     "// TODO[2022-09-17;by=alice;snoozed=2]: implement",
+		},
+		{
+			name: "works, metadata is dropped without {{.Meta}}",
+			config: contracts.ParsingConfig{
+				CommentPrefixes: []string{"//"},
+				Prefixes:        []string{"TODO"},
+				ExpiryPattern:   "{{.Prefix}}(?:\\[{{.Date}}\\])?",
+				DateLayout:      "2006-01-02",
+				CaseSensitive:   true,
+			},
+			line:     "// TODO: implement",
+			metadata: map[string]string{"snoozed": "1"},
+			want:     "// TODO[2022-09-17]: implement",
+		},
+		{
+			name: "works, custom pattern and layout",
+			config: contracts.ParsingConfig{
+				CommentPrefixes: []string{"//"},
+				Prefixes:        []string{"TODO"},
+				ExpiryPattern:   "{{.Prefix}}(?:\\s*->\\s*{{.Date}})?:",
+				DateLayout:      "02/01/2006",
+				CaseSensitive:   true,
+			},
+			line: "// TODO: implement",
+			want: "// TODO->17/09/2022: implement",
+		},
+		{
+			name: "works, case insensitive",
+			config: contracts.ParsingConfig{
+				CommentPrefixes: []string{"//"},
+				Prefixes:        []string{"TODO"},
+				ExpiryPattern:   "{{.Prefix}}(?:@{{.Date}})?",
+				DateLayout:      "2006-01-02",
+			},
+			line: "// todo later",
+			want: "// todo@2022-09-17 later",
+		},
+		{
+			name:    "fails, no comment",
+			config:  defaultConfig,
+			line:    "foo()",
+			wantErr: true,
+		},
+		{
+			name: "fails, pattern cannot be rendered",
+			config: contracts.ParsingConfig{
+				CommentPrefixes: []string{"//"},
+				Prefixes:        []string{"TODO"},
+				ExpiryPattern:   "{{.Prefix}}.?{{.Date}}",
+				DateLayout:      "2006-01-02",
+				CaseSensitive:   true,
+			},
+			line:    "// TODO implement",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			me, err := New(logrus.New(), tt.config)
+			if err != nil {
+				t.Fatalf("failed to create parser: %v", err)
+			}
+
+			result, err := me.Rewrite(tt.line, expiry, tt.metadata)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func Test_ParseSuppressions(t *testing.T) {
+	config := contracts.ParsingConfig{
+		CommentPrefixes: []string{"//", "#", "/*"},
+		Prefixes:        []string{"TODO"},
+		ExpiryPattern:   "{{.Prefix}}(?:\\[{{.Date}}\\])?",
+		DateLayout:      "2006-01-02",
+		CaseSensitive:   true,
+	}
+
+	tests := []struct {
+		name string
+		file string
+		want []contracts.Suppression
+	}{
+		{
+			name: "works without suppressions",
+			file: "// TODO: gofixit:ignore -- not a directive\nint main() {}\n",
+			want: []contracts.Suppression{},
+		},
+		{
+			name: "ignores directives in strings",
+			file: "x := \"// " + contracts.SuppressionDirective + "ignore\"\n" +
+				"print('#" + contracts.SuppressionDirective + "skip -- reason')\n",
+			want: []contracts.Suppression{},
+		},
+		{
+			name: "works",
+			file: "// gofixit:ignore-file -- generated\n" +
+				"x = 1 # " + contracts.SuppressionDirective + "ignore overdue -- waiting for v2\n" +
+				"/* gofixit:disable missing-expiry -- legacy code */\r\n" +
+				"\t// gofixit:enable missing-expiry\n" +
+				"// TODO[2022-06-15]: later // " + contracts.SuppressionDirective + "ignore --  vendor bug  \n",
+			want: []contracts.Suppression{
+				{
+					Kind:         contracts.SuppressionIgnoreFile,
+					Reason:       "generated",
+					LineNumber:   1,
+					Column:       1,
+					OriginalLine: "// gofixit:ignore-file -- generated",
+					SourceLine:   "// gofixit:ignore-file -- generated",
+				},
+				{
+					Kind:         contracts.SuppressionIgnore,
+					Rule:         contracts.RuleOverdue,
+					Reason:       "waiting for v2",
+					LineNumber:   2,
+					Column:       7,
+					OriginalLine: "# gofixit:ignore overdue -- waiting for v2",
+					SourceLine:   "x = 1 # gofixit:ignore overdue -- waiting for v2",
+				},
+				{
+					Kind:         contracts.SuppressionDisable,
+					Rule:         contracts.RuleMissingExpiry,
+					Reason:       "legacy code",
+					LineNumber:   3,
+					Column:       1,
+					OriginalLine: "/* gofixit:disable missing-expiry -- legacy code */\r",
+					SourceLine:   "/* gofixit:disable missing-expiry -- legacy code */\r",
+				},
+				{
+					Kind:         contracts.SuppressionEnable,
+					Rule:         contracts.RuleMissingExpiry,
+					LineNumber:   4,
+					Column:       2,
+					OriginalLine: "// gofixit:enable missing-expiry",
+					SourceLine:   "\t// gofixit:enable missing-expiry",
+				},
+				{
+					Kind:         contracts.SuppressionIgnore,
+					Reason:       "vendor bug",
+					LineNumber:   5,
+					Column:       28,
+					OriginalLine: "// gofixit:ignore --  vendor bug  ",
+					SourceLine:   "// TODO[2022-06-15]: later // gofixit:ignore --  vendor bug  ",
+				},
+			},
+		},
+		{
+			name: "reports invalid directives",
+			file: "// gofixit:ignore\n" +
+				"// gofixit:ignore overdue\n" +
+				"// gofixit:ignore because it is broken\n" +
+				"// gofixit:skip -- reason\n" +
+				"// gofixit:ignored -- reason\n",
+			want: []contracts.Suppression{
+				{
+					Kind:         contracts.SuppressionIgnore,
+					Invalid:      "gofixit:ignore is missing a reason, e.g. gofixit:ignore -- why",
+					LineNumber:   1,
+					Column:       1,
+					OriginalLine: "// gofixit:ignore",
+					SourceLine:   "// gofixit:ignore",
+				},
+				{
+					Kind:         contracts.SuppressionIgnore,
+					Rule:         contracts.RuleOverdue,
+					Invalid:      "gofixit:ignore is missing a reason, e.g. gofixit:ignore -- why",
+					LineNumber:   2,
+					Column:       1,
+					OriginalLine: "// gofixit:ignore overdue",
+					SourceLine:   "// gofixit:ignore overdue",
+				},
+				{
+					Kind:         contracts.SuppressionIgnore,
+					Invalid:      "gofixit:ignore expects a single rule, separate the reason with --",
+					LineNumber:   3,
+					Column:       1,
+					OriginalLine: "// gofixit:ignore because it is broken",
+					SourceLine:   "// gofixit:ignore because it is broken",
+				},
+				{
+					Kind:         "skip",
+					Invalid:      "unknown directive gofixit:skip",
+					LineNumber:   4,
+					Column:       1,
+					OriginalLine: "// gofixit:skip -- reason",
+					SourceLine:   "// gofixit:skip -- reason",
+				},
+				{
+					Kind:         "ignored",
+					Invalid:      "unknown directive gofixit:ignored",
+					LineNumber:   5,
+					Column:       1,
+					OriginalLine: "// gofixit:ignored -- reason",
+					SourceLine:   "// gofixit:ignored -- reason",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			me, err := New(logrus.New(), config)
+			if err != nil {
+				t.Fatalf("failed to create parser: %v", err)
+			}
+
+			result, err := me.ParseSuppressions(tt.file)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
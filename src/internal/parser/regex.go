@@ -2,9 +2,9 @@ package parser
 
 import (
 	"fmt"
-	"html/template"
 	"regexp"
 	"strings"
+	"text/template"
 	"unicode"
 
 	"github.com/LouisBrunner/gofixit/src/contracts"
@@ -28,65 +28,71 @@ func layoutToRegex(layout string) (string, error) {
 }
 
 const (
-	matchEverything = iota + 1
-	matchComment
-	matchPrefix
-	matchExpiry
-	matchContent
-	expectedMatches
+	groupEverything = "everything"
+	groupComment    = "comment"
+	groupPrefix     = "prefix"
+	groupOwner      = "owner"
+	groupExpiry     = "expiry"
+	groupContent    = "content"
+	groupTag        = "tag"
+	groupMeta       = "meta"
 )
 
-type ordering struct {
-	matchEverything int
-	matchComment    int
-	matchPrefix     int
-	matchExpiry     int
-	matchContent    int
+type groups struct {
+	everything int
+	comment    int
+	prefix     int
+	owner      int
+	expiry     int
+	content    int
+	tag        int
+	meta       int
 }
 
-func buildRE(logger *logrus.Logger, config contracts.ParsingConfig) (*regexp.Regexp, *ordering, error) {
-	order := ordering{
-		matchEverything: matchEverything,
-		matchComment:    matchComment,
-		matchPrefix:     matchPrefix,
-		matchExpiry:     matchExpiry,
-		matchContent:    matchContent,
-	}
+type templateVars struct {
+	Prefix string
+	Date   string
+	Owner  string
+	// Meta holds `;key=value` pairs, e.g. `;snoozed=2`
+	Meta string
+}
 
-	idxPrefix := strings.Index(config.ExpiryPattern, ".Prefix")
-	if idxPrefix == -1 {
-		return nil, nil, fmt.Errorf("expiry template must contain {{.Prefix}}")
-	}
-	idxExpiry := strings.Index(config.ExpiryPattern, ".Date")
-	if idxExpiry == -1 {
-		return nil, nil, fmt.Errorf("expiry template must contain {{.Date}}")
-	}
-	if idxExpiry < idxPrefix {
-		order.matchPrefix = matchExpiry
-		order.matchExpiry = matchPrefix
+func parseTemplate(config contracts.ParsingConfig) (*template.Template, error) {
+	if !strings.Contains(config.ExpiryPattern, ".Prefix") {
+		return nil, fmt.Errorf("expiry template must contain {{.Prefix}}")
 	}
-
-	tmpl, err := template.New("partWithExpiry").Parse(config.ExpiryPattern)
-	if err != nil {
-		return nil, nil, err
+	if !strings.Contains(config.ExpiryPattern, ".Date") {
+		return nil, fmt.Errorf("expiry template must contain {{.Date}}")
 	}
+	return template.New("partWithExpiry").Parse(config.ExpiryPattern)
+}
 
+func buildRE(logger *logrus.Logger, config contracts.ParsingConfig, tmpl *template.Template) (*regexp.Regexp, *groups, error) {
 	dateRegex, err := layoutToRegex(config.DateLayout)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	patternBuilder := &strings.Builder{}
-	err = tmpl.Execute(patternBuilder, struct {
-		Prefix string
-		Date   string
-	}{
-		Prefix: fmt.Sprintf("(%s)", strings.Join(utils.MapSlice(config.Prefixes, regexp.QuoteMeta), "|")),
-		Date:   fmt.Sprintf("((?:%s)?)", dateRegex),
+	err = tmpl.Execute(patternBuilder, templateVars{
+		Prefix: fmt.Sprintf("(?P<%s>%s)", groupPrefix, strings.Join(utils.MapSlice(config.Prefixes, regexp.QuoteMeta), "|")),
+		Date:   fmt.Sprintf("(?P<%s>(?:%s)?)", groupExpiry, dateRegex),
+		Owner:  fmt.Sprintf("(?P<%s>[^()]*)", groupOwner),
+		Meta:   fmt.Sprintf("(?P<%s>(?:;[[:alnum:]_-]+=[^;[:space:]()\\[\\]]*)*)", groupMeta),
 	})
 	if err != nil {
 		return nil, nil, err
 	}
+	for placeholder, group := range map[string]string{
+		"Prefix": groupPrefix,
+		"Date":   groupExpiry,
+		"Owner":  groupOwner,
+		"Meta":   groupMeta,
+	} {
+		if strings.Count(patternBuilder.String(), fmt.Sprintf("(?P<%s>", group)) > 1 {
+			return nil, nil, fmt.Errorf("expiry template can only contain {{.%s}} once", placeholder)
+		}
+	}
 
 	flags := "(?i)"
 	if config.CaseSensitive {
@@ -94,13 +100,29 @@ func buildRE(logger *logrus.Logger, config contracts.ParsingConfig) (*regexp.Reg
 	}
 
 	literal := fmt.Sprintf(
-		"%s((%s)[[:space:]]*%s[[:space:]]*(.+)?)$",
+		"%s(?P<%s>(?P<%s>%s)[[:space:]]*(?P<%s>%s)[[:space:]]*(?P<%s>.+)?)$",
 		flags,
+		groupEverything,
+		groupComment,
 		strings.Join(utils.MapSlice(config.CommentPrefixes, regexp.QuoteMeta), "|"),
+		groupTag,
 		patternBuilder.String(),
+		groupContent,
 	)
 	logger.Infof("using regex %q to parse comments", literal)
 
 	re, err := regexp.Compile(literal)
-	return re, &order, err
+	if err != nil {
+		return nil, nil, err
+	}
+	return re, &groups{
+		everything: re.SubexpIndex(groupEverything),
+		comment:    re.SubexpIndex(groupComment),
+		prefix:     re.SubexpIndex(groupPrefix),
+		owner:      re.SubexpIndex(groupOwner),
+		expiry:     re.SubexpIndex(groupExpiry),
+		content:    re.SubexpIndex(groupContent),
+		tag:        re.SubexpIndex(groupTag),
+		meta:       re.SubexpIndex(groupMeta),
+	}, nil
 }
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+	"text/template"
+
+	"github.com/LouisBrunner/gofixit/src/utils"
+)
+
+// placeholders stand for the template variables while turning the expiry pattern back into text, they are
+// private use characters so they can't clash with anything written by users
+const (
+	placeholderPrefix = '\uE000'
+	placeholderOwner  = '\uE001'
+	placeholderDate   = '\uE002'
+	placeholderMeta   = '\uE003'
+)
+
+// renderer writes tags (prefix, owner and expiry) following the expiry pattern
+type renderer struct {
+	pattern *syntax.Regexp
+}
+
+func newRenderer(tmpl *template.Template) (*renderer, error) {
+	builder := &strings.Builder{}
+	err := tmpl.Execute(builder, templateVars{
+		Prefix: string(placeholderPrefix),
+		Date:   string(placeholderDate),
+		Owner:  string(placeholderOwner),
+		Meta:   string(placeholderMeta),
+	})
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := syntax.Parse(builder.String(), syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	return &renderer{pattern: pattern}, nil
+}
+
+func (me *renderer) render(prefix, owner, date, meta string) (string, error) {
+	text, _, err := literalize(me.pattern, map[rune]string{
+		placeholderPrefix: prefix,
+		placeholderOwner:  owner,
+		placeholderDate:   date,
+		placeholderMeta:   meta,
+	})
+	return text, err
+}
+
+// literalize produces the simplest text matched by the regex: optional parts are only kept when they contain a
+// non-empty value and alternations use their first choice, it also reports whether a value was used
+func literalize(re *syntax.Regexp, values map[rune]string) (string, bool, error) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		builder := &strings.Builder{}
+		used := false
+		for _, r := range re.Rune {
+			value, isPlaceholder := values[r]
+			if !isPlaceholder {
+				builder.WriteRune(r)
+				continue
+			}
+			builder.WriteString(value)
+			used = used || value != ""
+		}
+		return builder.String(), used, nil
+	case syntax.OpCapture:
+		return literalize(re.Sub[0], values)
+	case syntax.OpConcat:
+		builder := &strings.Builder{}
+		used := false
+		for _, sub := range re.Sub {
+			text, subUsed, err := literalize(sub, values)
+			if err != nil {
+				return "", false, err
+			}
+			builder.WriteString(text)
+			used = used || subUsed
+		}
+		return builder.String(), used, nil
+	case syntax.OpQuest, syntax.OpStar:
+		text, used, err := literalize(re.Sub[0], values)
+		if err != nil || !used {
+			return "", false, err
+		}
+		return text, used, nil
+	case syntax.OpPlus:
+		return literalize(re.Sub[0], values)
+	case syntax.OpRepeat:
+		text, used, err := literalize(re.Sub[0], values)
+		if err != nil || (re.Min == 0 && !used) {
+			return "", false, err
+		}
+		return strings.Repeat(text, utils.Max(re.Min, 1)), used, nil
+	case syntax.OpAlternate:
+		first := ""
+		for i, sub := range re.Sub {
+			text, used, err := literalize(sub, values)
+			if err != nil {
+				return "", false, err
+			}
+			if used {
+				return text, used, nil
+			}
+			if i == 0 {
+				first = text
+			}
+		}
+		return first, false, nil
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return "", false, fmt.Errorf("cannot render empty character class")
+		}
+		return string(re.Rune[0]), false, nil
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return "", false, nil
+	}
+	return "", false, fmt.Errorf("cannot render %q in the expiry pattern", re.String())
+}
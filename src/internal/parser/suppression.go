@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	directiveSeparator = "--"
+	blockCommentEnd    = "*/"
+)
+
+// buildDirectiveRE only matches directives starting the body of a comment which itself starts the line or follows
+// whitespace, so that directives quoted in strings (e.g. `"// gofixit:ignore"`) are not picked up
+func buildDirectiveRE(logger *logrus.Logger, config contracts.ParsingConfig) (*regexp.Regexp, error) {
+	literal := fmt.Sprintf(
+		"(?:^|[[:space:]])((?:%s)[[:space:]]*%s([[:alnum:]-]*)(.*))$",
+		strings.Join(utils.MapSlice(config.CommentPrefixes, regexp.QuoteMeta), "|"),
+		regexp.QuoteMeta(contracts.SuppressionDirective),
+	)
+	logger.Infof("using regex %q to parse suppressions", literal)
+	return regexp.Compile(literal)
+}
+
+// parseDirective reads `[rule] -- reason` following the kind of the directive
+func parseDirective(kind contracts.SuppressionKind, rest string) contracts.Suppression {
+	suppression := contracts.Suppression{
+		Kind: kind,
+	}
+	switch kind {
+	case contracts.SuppressionIgnore, contracts.SuppressionIgnoreFile, contracts.SuppressionDisable, contracts.SuppressionEnable:
+	default:
+		suppression.Invalid = fmt.Sprintf("unknown directive %s%s", contracts.SuppressionDirective, kind)
+		return suppression
+	}
+	if rest != "" && !strings.HasPrefix(rest, " ") && !strings.HasPrefix(rest, "\t") {
+		suppression.Invalid = fmt.Sprintf("unknown directive %s%s%s", contracts.SuppressionDirective, kind, strings.Fields(rest)[0])
+		return suppression
+	}
+
+	rest = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rest), blockCommentEnd))
+	rule, reason, _ := strings.Cut(rest, directiveSeparator)
+	rule = strings.TrimSpace(rule)
+	suppression.Reason = strings.TrimSpace(reason)
+	if strings.ContainsAny(rule, " \t") {
+		suppression.Invalid = fmt.Sprintf("%s%s expects a single rule, separate the reason with %s", contracts.SuppressionDirective, kind, directiveSeparator)
+		return suppression
+	}
+	suppression.Rule = contracts.Rule(rule)
+	if suppression.Reason == "" && kind != contracts.SuppressionEnable {
+		suppression.Invalid = fmt.Sprintf("%s%s is missing a reason, e.g. %s%s %s why", contracts.SuppressionDirective, kind, contracts.SuppressionDirective, kind, directiveSeparator)
+	}
+	return suppression
+}
+
+func (me *parserImpl) ParseSuppressions(fileContent string) ([]contracts.Suppression, error) {
+	lines := strings.Split(fileContent, "\n")
+
+	results := []contracts.Suppression{}
+	for num, line := range lines {
+		indices := me.directive.FindStringSubmatchIndex(line)
+		if indices == nil {
+			continue
+		}
+		me.logger.Infof("suppression matched %q", submatch(line, indices, 1))
+		suppression := parseDirective(contracts.SuppressionKind(submatch(line, indices, 2)), submatch(line, indices, 3))
+		suppression.LineNumber = uint(num) + 1
+		suppression.Column = uint(utf8.RuneCountInString(line[:indices[2]])) + 1
+		suppression.OriginalLine = submatch(line, indices, 1)
+		suppression.SourceLine = line
+		results = append(results, suppression)
+	}
+	return results, nil
+}
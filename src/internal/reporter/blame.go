@@ -0,0 +1,48 @@
+package reporter
+
+import (
+	"fmt"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+)
+
+const shortCommitLength = 8
+
+type jsonBlame struct {
+	Commit      string `json:"commit"`
+	Author      string `json:"author"`
+	AuthorEmail string `json:"author_email"`
+	Date        string `json:"date"`
+}
+
+func toJSONBlame(blame *contracts.Blame) *jsonBlame {
+	if blame == nil {
+		return nil
+	}
+	return &jsonBlame{
+		Commit:      blame.Commit,
+		Author:      blame.Author,
+		AuthorEmail: blame.AuthorEmail,
+		Date:        blame.Date.Format(jsonDateLayout),
+	}
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > shortCommitLength {
+		return commit[:shortCommitLength]
+	}
+	return commit
+}
+
+// describeBlame summarizes the commit which last changed a line, e.g. `alice <alice@example.com> in 1a2b3c4d on 2023-01-04`
+func describeBlame(blame *contracts.Blame) string {
+	return fmt.Sprintf("%s <%s> in %s on %s", blame.Author, blame.AuthorEmail, shortCommit(blame.Commit), blame.Date.Format(jsonDateLayout))
+}
+
+// withBlame appends the blame of the comment to a message, when known
+func withBlame(message string, comment contracts.ParsedComment) string {
+	if comment.Blame == nil {
+		return message
+	}
+	return fmt.Sprintf("%s (last changed by %s)", message, describeBlame(comment.Blame))
+}
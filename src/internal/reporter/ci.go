@@ -0,0 +1,133 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+)
+
+var (
+	githubDataEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+	)
+	githubPropertyEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+		":", "%3A",
+		",", "%2C",
+	)
+)
+
+func githubCommand(severity contracts.Severity) string {
+	switch severity {
+	case contracts.SeverityError:
+		return "error"
+	}
+	return "warning"
+}
+
+func formatGitHub(out io.Writer, report contracts.Report) error {
+	for _, result := range report.Results {
+		if result.Violation == nil {
+			continue
+		}
+		_, err := fmt.Fprintf(
+			out,
+			"::%s file=%s,line=%d,col=%d,title=%s::%s\n",
+			githubCommand(result.Violation.Severity),
+			githubPropertyEscaper.Replace(result.Path),
+			result.Comment.LineNumber,
+			result.Comment.Column,
+			githubPropertyEscaper.Replace(fmt.Sprintf("%s (%s)", toolName, result.Violation.Rule)),
+			githubDataEscaper.Replace(withBlame(result.Violation.Message, result.Comment)),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	for _, entry := range utils.SortedMap(report.Errors) {
+		_, err := fmt.Fprintf(
+			out,
+			"::error file=%s,title=%s::%s\n",
+			githubPropertyEscaper.Replace(entry.Key),
+			githubPropertyEscaper.Replace(toolName),
+			githubDataEscaper.Replace(entry.Value.Error()),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type gitlabLines struct {
+	Begin uint `json:"begin"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+func gitlabSeverity(severity contracts.Severity) string {
+	switch severity {
+	case contracts.SeverityError:
+		return "major"
+	}
+	return "minor"
+}
+
+func formatGitLab(out io.Writer, report contracts.Report) error {
+	violations := []contracts.Result{}
+	for _, result := range report.Results {
+		if result.Violation != nil {
+			violations = append(violations, result)
+		}
+	}
+	hashes := fingerprints(violations)
+
+	issues := make([]gitlabIssue, 0, len(violations)+len(report.Errors))
+	for i, result := range violations {
+		issues = append(issues, gitlabIssue{
+			Description: withBlame(result.Violation.Message, result.Comment),
+			CheckName:   string(result.Violation.Rule),
+			Fingerprint: hashes[i],
+			Severity:    gitlabSeverity(result.Violation.Severity),
+			Location: gitlabLocation{
+				Path:  result.Path,
+				Lines: gitlabLines{Begin: result.Comment.LineNumber},
+			},
+		})
+	}
+	for _, entry := range utils.SortedMap(report.Errors) {
+		issues = append(issues, gitlabIssue{
+			Description: entry.Value.Error(),
+			CheckName:   "error",
+			Fingerprint: fingerprints([]contracts.Result{{Path: entry.Key}})[0],
+			Severity:    "critical",
+			Location: gitlabLocation{
+				Path:  entry.Key,
+				Lines: gitlabLines{Begin: 1},
+			},
+		})
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(issues)
+}
@@ -0,0 +1,26 @@
+package reporter
+
+import "fmt"
+
+var colors = map[string]int{
+	"bold":    1,
+	"dim":     2,
+	"red":     31,
+	"green":   32,
+	"yellow":  33,
+	"blue":    34,
+	"magenta": 35,
+	"cyan":    36,
+	"gray":    90,
+}
+
+func colorize(enabled bool, name string, text string) (string, error) {
+	code, found := colors[name]
+	if !found {
+		return "", fmt.Errorf("unknown color %q", name)
+	}
+	if !enabled || text == "" {
+		return text, nil
+	}
+	return fmt.Sprintf("\x1b[%dm%s\x1b[0m", code, text), nil
+}
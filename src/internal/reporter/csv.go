@@ -0,0 +1,63 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+)
+
+var csvHeader = []string{"path", "line", "prefix", "expiry", "days_remaining", "owner", "status", "content", "author", "author_email", "commit", "commit_date", "code_owners"}
+
+// daysUntil counts calendar days from now until the expiry, negative once it has passed
+func daysUntil(now time.Time, expiry time.Time) int {
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func newCSVFormatter(config contracts.ReporterConfig) (formatter, error) {
+	return func(out io.Writer, report contracts.Report) error {
+		writer := csv.NewWriter(out)
+		err := writer.Write(csvHeader)
+		if err != nil {
+			return err
+		}
+		for _, result := range report.Results {
+			comment := result.Comment
+			expiry, remaining := comment.InvalidExpiry, ""
+			author, email, commit, date := "", "", "", ""
+			if comment.Blame != nil {
+				author, email, commit = comment.Blame.Author, comment.Blame.AuthorEmail, comment.Blame.Commit
+				date = comment.Blame.Date.Format(jsonDateLayout)
+			}
+			if comment.Expiry != nil {
+				expiry = comment.Expiry.Format(jsonDateLayout)
+				remaining = strconv.Itoa(daysUntil(config.Now, *comment.Expiry))
+			}
+			err := writer.Write([]string{
+				result.Path,
+				strconv.FormatUint(uint64(comment.LineNumber), 10),
+				comment.Prefix,
+				expiry,
+				remaining,
+				comment.Owner,
+				string(result.Status),
+				comment.Content,
+				author,
+				email,
+				commit,
+				date,
+				strings.Join(result.CodeOwners, " "),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}, nil
+}
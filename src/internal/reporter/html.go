@@ -0,0 +1,243 @@
+package reporter
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+)
+
+//go:embed html.tmpl
+var htmlTemplate string
+
+const (
+	htmlTimelineMonths = 12
+	htmlBarWidth       = 44
+	htmlBarGap         = 8
+	htmlChartHeight    = 140
+)
+
+// statusOrder is used to sort the status column from the most to the least urgent
+var statusOrder = map[contracts.Status]int{
+	contracts.StatusOverdue: 0,
+	contracts.StatusInvalid: 1,
+	contracts.StatusWarning: 2,
+	contracts.StatusUndated: 3,
+	contracts.StatusOK:      4,
+}
+
+type htmlRow struct {
+	Path      string
+	Directory string
+	Line      uint
+	Prefix    string
+	Owner     string
+	Content   string
+	Expiry    string
+	Remaining string
+	Status    contracts.Status
+	Order     int
+	Message   string
+	// LastChange and Commit describe the blame of the comment, when known
+	LastChange string
+	Author     string
+	Commit     string
+}
+
+type htmlBar struct {
+	Label  string
+	Count  int
+	X      int
+	Center int
+	Y      int
+	Height int
+	Class  string
+}
+
+type htmlTimeline struct {
+	Width    int
+	Height   int
+	BarWidth int
+	Bars     []htmlBar
+}
+
+type htmlCount struct {
+	Status contracts.Status
+	Count  int
+}
+
+type htmlPage struct {
+	Generated   string
+	Rows        []htmlRow
+	Counts      []htmlCount
+	Owners      []string
+	Prefixes    []string
+	Directories []string
+	Timeline    htmlTimeline
+	Errors      []jsonError
+	Summary     contracts.Summary
+}
+
+func directoryOf(name string) string {
+	return path.Dir(filepath.ToSlash(name))
+}
+
+// ancestors lists a directory and all its parents, so filtering on `src` also shows `src/sub`
+func ancestors(dir string) []string {
+	all := []string{}
+	for dir != "." && dir != "/" && dir != "" {
+		all = append(all, dir)
+		dir = path.Dir(dir)
+	}
+	return all
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+// buildTimeline groups expiries by month, starting with what is already overdue and ending with everything
+// after the displayed months
+func buildTimeline(now time.Time, results []contracts.Result) htmlTimeline {
+	labels := []string{"overdue"}
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	for i := 0; i < htmlTimelineMonths; i++ {
+		labels = append(labels, start.AddDate(0, i, 0).Format("Jan 06"))
+	}
+	labels = append(labels, "later")
+
+	counts := make([]int, len(labels))
+	for _, result := range results {
+		expiry := result.Comment.Expiry
+		if expiry == nil {
+			continue
+		}
+		if now.After(*expiry) {
+			counts[0] += 1
+			continue
+		}
+		counts[1+utils.Min(monthsBetween(start, *expiry), htmlTimelineMonths)] += 1
+	}
+
+	highest := 1
+	for _, count := range counts {
+		highest = utils.Max(highest, count)
+	}
+
+	timeline := htmlTimeline{
+		Width:    len(labels)*(htmlBarWidth+htmlBarGap) + htmlBarGap,
+		Height:   htmlChartHeight + 40,
+		BarWidth: htmlBarWidth,
+	}
+	for i, label := range labels {
+		height := counts[i] * htmlChartHeight / highest
+		class := "upcoming"
+		if i == 0 {
+			class = "overdue"
+		}
+		timeline.Bars = append(timeline.Bars, htmlBar{
+			Label:  label,
+			Count:  counts[i],
+			X:      htmlBarGap + i*(htmlBarWidth+htmlBarGap),
+			Center: htmlBarGap + i*(htmlBarWidth+htmlBarGap) + htmlBarWidth/2,
+			Y:      htmlChartHeight - height + 20,
+			Height: height,
+			Class:  class,
+		})
+	}
+	return timeline
+}
+
+func newHTMLFormatter(config contracts.ReporterConfig) (formatter, error) {
+	tmpl, err := template.New("report").Parse(htmlTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(out io.Writer, report contracts.Report) error {
+		page := htmlPage{
+			Generated: config.Now.Format("2006-01-02 15:04"),
+			Errors:    toJSONErrors(report.Errors),
+			Summary:   report.Summary,
+			Timeline:  buildTimeline(config.Now, report.Results),
+		}
+
+		owners := map[string]struct{}{}
+		prefixes := map[string]struct{}{}
+		directories := map[string]struct{}{}
+		counts := map[contracts.Status]int{}
+		for _, result := range report.Results {
+			comment := result.Comment
+			row := htmlRow{
+				Path:      result.Path,
+				Directory: directoryOf(result.Path),
+				Line:      comment.LineNumber,
+				Prefix:    comment.Prefix,
+				Owner:     comment.Owner,
+				Content:   comment.Content,
+				Expiry:    comment.InvalidExpiry,
+				Status:    result.Status,
+				Order:     statusOrder[result.Status],
+			}
+			if comment.Expiry != nil {
+				row.Expiry = comment.Expiry.Format(jsonDateLayout)
+				remaining, err := relativeTime(config.Now, comment.Expiry)
+				if err != nil {
+					return err
+				}
+				row.Remaining = remaining
+			}
+			if result.Violation != nil {
+				row.Message = result.Violation.Message
+			}
+			if comment.Blame != nil {
+				row.LastChange = comment.Blame.Date.Format(jsonDateLayout)
+				row.Author = fmt.Sprintf("%s <%s>", comment.Blame.Author, comment.Blame.AuthorEmail)
+				row.Commit = shortCommit(comment.Blame.Commit)
+			}
+			page.Rows = append(page.Rows, row)
+
+			owners[comment.Owner] = struct{}{}
+			prefixes[comment.Prefix] = struct{}{}
+			for _, dir := range ancestors(row.Directory) {
+				directories[dir] = struct{}{}
+			}
+			counts[result.Status] += 1
+		}
+		delete(owners, "")
+		page.Owners = sortedKeys(owners)
+		page.Prefixes = sortedKeys(prefixes)
+		page.Directories = sortedKeys(directories)
+		for _, status := range []contracts.Status{
+			contracts.StatusOverdue,
+			contracts.StatusInvalid,
+			contracts.StatusWarning,
+			contracts.StatusUndated,
+			contracts.StatusOK,
+		} {
+			page.Counts = append(page.Counts, htmlCount{Status: status, Count: counts[status]})
+		}
+
+		err := tmpl.Execute(out, page)
+		if err != nil {
+			return fmt.Errorf("failed to render html: %w", err)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,93 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+)
+
+const (
+	icalDateLayout      = "20060102"
+	icalTimestampLayout = "20060102T150405Z"
+	icalLineLength      = 75
+)
+
+var icalEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	";", "\\;",
+	",", "\\,",
+	"\r\n", "\\n",
+	"\n", "\\n",
+	"\r", "\\n",
+)
+
+// icalFold splits content lines longer than 75 octets without breaking UTF-8 sequences (RFC 5545, 3.1)
+func icalFold(line string) string {
+	folded := &strings.Builder{}
+	limit := icalLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut -= 1
+		}
+		folded.WriteString(line[:cut])
+		folded.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space of continuation lines counts towards their length
+		limit = icalLineLength - 1
+	}
+	folded.WriteString(line)
+	return folded.String()
+}
+
+func newICalFormatter(config contracts.ReporterConfig) (formatter, error) {
+	return func(out io.Writer, report contracts.Report) error {
+		// UIDs must not change when a comment becomes overdue, so they ignore violations
+		dated := []contracts.Result{}
+		for _, result := range report.Results {
+			if result.Comment.Expiry == nil {
+				continue
+			}
+			result.Violation = nil
+			dated = append(dated, result)
+		}
+		uids := fingerprints(dated)
+
+		lines := []string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			fmt.Sprintf("PRODID:-//%s//%s//EN", toolName, toolName),
+			"CALSCALE:GREGORIAN",
+			"METHOD:PUBLISH",
+		}
+		stamp := config.Now.UTC().Format(icalTimestampLayout)
+		for i, result := range dated {
+			comment := result.Comment
+			expiry := *comment.Expiry
+			summary := strings.TrimSpace(fmt.Sprintf("%s %s", comment.Prefix, strings.TrimSpace(comment.Content)))
+			lines = append(lines,
+				"BEGIN:VEVENT",
+				fmt.Sprintf("UID:%s@%s", uids[i][:32], toolName),
+				fmt.Sprintf("DTSTAMP:%s", stamp),
+				fmt.Sprintf("DTSTART;VALUE=DATE:%s", expiry.Format(icalDateLayout)),
+				fmt.Sprintf("DTEND;VALUE=DATE:%s", expiry.AddDate(0, 0, 1).Format(icalDateLayout)),
+				fmt.Sprintf("SUMMARY:%s", icalEscaper.Replace(summary)),
+				fmt.Sprintf("DESCRIPTION:%s", icalEscaper.Replace(withBlame(fmt.Sprintf("%s:%d", result.Path, comment.LineNumber), comment))),
+				"TRANSP:TRANSPARENT",
+				"END:VEVENT",
+			)
+		}
+		lines = append(lines, "END:VCALENDAR")
+
+		for _, line := range lines {
+			_, err := io.WriteString(out, icalFold(line)+"\r\n")
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
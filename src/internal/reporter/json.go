@@ -0,0 +1,126 @@
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+)
+
+const jsonDateLayout = "2006-01-02"
+
+type jsonResult struct {
+	Path          string             `json:"path"`
+	Line          uint               `json:"line"`
+	Column        uint               `json:"column"`
+	CommentPrefix string             `json:"comment_prefix"`
+	Prefix        string             `json:"prefix"`
+	Content       string             `json:"content"`
+	Expiry        *string            `json:"expiry"`
+	Owner         string             `json:"owner,omitempty"`
+	Severity      contracts.Severity `json:"severity,omitempty"`
+	Rule          contracts.Rule     `json:"rule,omitempty"`
+	Message       string             `json:"message,omitempty"`
+	Blame         *jsonBlame         `json:"blame,omitempty"`
+	CodeOwners    []string           `json:"code_owners,omitempty"`
+}
+
+type jsonError struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+type jsonSummary struct {
+	Files      int `json:"files"`
+	Comments   int `json:"comments"`
+	Violations int `json:"violations"`
+	Errors     int `json:"errors"`
+}
+
+// ndjson lines are tagged with their type, as results, errors and the summary share the same stream
+const (
+	ndjsonResult  = "result"
+	ndjsonError   = "error"
+	ndjsonSummary = "summary"
+)
+
+type ndjsonResultLine struct {
+	Type string `json:"type"`
+	jsonResult
+}
+
+type ndjsonErrorLine struct {
+	Type string `json:"type"`
+	jsonError
+}
+
+type ndjsonSummaryLine struct {
+	Type string `json:"type"`
+	jsonSummary
+}
+
+type jsonReport struct {
+	Results []jsonResult `json:"results"`
+	Errors  []jsonError  `json:"errors"`
+	Summary jsonSummary  `json:"summary"`
+}
+
+func toJSONResult(result contracts.Result) jsonResult {
+	converted := jsonResult{
+		Path:          result.Path,
+		Line:          result.Comment.LineNumber,
+		Column:        result.Comment.Column,
+		CommentPrefix: result.Comment.CommentPrefix,
+		Prefix:        result.Comment.Prefix,
+		Content:       result.Comment.Content,
+		Owner:         result.Comment.Owner,
+		Blame:         toJSONBlame(result.Comment.Blame),
+		CodeOwners:    result.CodeOwners,
+	}
+	if result.Comment.Expiry != nil {
+		converted.Expiry = utils.Pointerize(result.Comment.Expiry.Format(jsonDateLayout))
+	}
+	if result.Violation != nil {
+		converted.Severity = result.Violation.Severity
+		converted.Rule = result.Violation.Rule
+		converted.Message = result.Violation.Message
+	}
+	return converted
+}
+
+func toJSONErrors(errors contracts.FilesErrors) []jsonError {
+	return utils.MapSlice(utils.SortedMap(errors), func(entry utils.MapEntry[string, error]) jsonError {
+		return jsonError{
+			Path:  entry.Key,
+			Error: entry.Value.Error(),
+		}
+	})
+}
+
+func formatJSON(out io.Writer, report contracts.Report) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jsonReport{
+		Results: utils.MapSlice(report.Results, toJSONResult),
+		Errors:  toJSONErrors(report.Errors),
+		Summary: jsonSummary(report.Summary),
+	})
+}
+
+func formatNDJSON(out io.Writer, report contracts.Report) error {
+	encoder := json.NewEncoder(out)
+	for _, result := range report.Results {
+		err := encoder.Encode(ndjsonResultLine{Type: ndjsonResult, jsonResult: toJSONResult(result)})
+		if err != nil {
+			return err
+		}
+	}
+	for _, failure := range toJSONErrors(report.Errors) {
+		err := encoder.Encode(ndjsonErrorLine{Type: ndjsonError, jsonError: failure})
+		if err != nil {
+			return err
+		}
+	}
+	return encoder.Encode(ndjsonSummaryLine{Type: ndjsonSummary, jsonSummary: jsonSummary(report.Summary)})
+}
@@ -0,0 +1,85 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"|", "\\|",
+	"`", "\\`",
+	"*", "\\*",
+	"_", "\\_",
+	"<", "&lt;",
+	">", "&gt;",
+	"\r", " ",
+	"\n", " ",
+)
+
+// markdownCode wraps a value in a code span, using a longer fence when it contains backticks
+func markdownCode(value string) string {
+	fence := "`"
+	for strings.Contains(value, fence) {
+		fence += "`"
+	}
+	padding := ""
+	if strings.HasPrefix(value, "`") || strings.HasSuffix(value, "`") {
+		padding = " "
+	}
+	return fence + padding + strings.ReplaceAll(value, "|", "\\|") + padding + fence
+}
+
+func formatMarkdown(out io.Writer, report contracts.Report) error {
+	perSeverity := map[contracts.Severity]int{}
+	violations := []contracts.Result{}
+	for _, result := range report.Results {
+		if result.Violation == nil {
+			continue
+		}
+		perSeverity[result.Violation.Severity] += 1
+		violations = append(violations, result)
+	}
+
+	doc := &strings.Builder{}
+	fmt.Fprintf(doc, "### %s\n\n", toolName)
+	if len(violations) == 0 {
+		fmt.Fprintf(doc, "No issues found in %s.\n", plural(len(report.Files), "file"))
+	} else {
+		fmt.Fprintf(doc, "| Severity | Count |\n| --- | ---: |\n")
+		for _, entry := range utils.SortedMap(perSeverity) {
+			fmt.Fprintf(doc, "| %s | %d |\n", entry.Key, entry.Value)
+		}
+
+		fmt.Fprintf(doc, "\n| Location | Severity | Rule | Message | Last change |\n| --- | --- | --- | --- | --- |\n")
+		for _, result := range violations {
+			change := "-"
+			if blame := result.Comment.Blame; blame != nil {
+				change = fmt.Sprintf("%s %s %s", blame.Date.Format(jsonDateLayout), markdownEscaper.Replace(blame.Author), markdownCode(shortCommit(blame.Commit)))
+			}
+			fmt.Fprintf(
+				doc,
+				"| %s | %s | %s | %s | %s |\n",
+				markdownCode(fmt.Sprintf("%s:%d", result.Path, result.Comment.LineNumber)),
+				result.Violation.Severity,
+				result.Violation.Rule,
+				markdownEscaper.Replace(result.Violation.Message),
+				change,
+			)
+		}
+	}
+
+	if len(report.Errors) > 0 {
+		fmt.Fprintf(doc, "\n%s could not be processed:\n\n", plural(len(report.Errors), "file"))
+		for _, entry := range utils.SortedMap(report.Errors) {
+			fmt.Fprintf(doc, "- %s: %s\n", markdownCode(entry.Key), markdownEscaper.Replace(entry.Value.Error()))
+		}
+	}
+
+	_, err := io.WriteString(out, doc.String())
+	return err
+}
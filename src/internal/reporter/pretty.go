@@ -0,0 +1,158 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+)
+
+type prettyPrinter struct {
+	out   io.Writer
+	color bool
+	err   error
+}
+
+func (me *prettyPrinter) printf(format string, args ...any) {
+	if me.err != nil {
+		return
+	}
+	_, me.err = fmt.Fprintf(me.out, format, args...)
+}
+
+func (me *prettyPrinter) paint(name string, text string) string {
+	painted, err := colorize(me.color, name, text)
+	if err != nil {
+		return text
+	}
+	return painted
+}
+
+func plural(count int, word string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, word)
+	}
+	return fmt.Sprintf("%d %ss", count, word)
+}
+
+// snippet returns the source line of a comment and the whitespace needed to put a caret under its column, keeping
+// tabs to stay aligned
+func snippet(comment contracts.ParsedComment) (string, string) {
+	line, column := comment.SourceLine, int(comment.Column)
+	if line == "" {
+		// only the matched comment is known, which starts at the column
+		line, column = comment.OriginalLine, 1
+	}
+	runes := []rune(line)
+	column = utils.Min(utils.Max(column-1, 0), len(runes))
+	return line, strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+		return ' '
+	}, string(runes[:column]))
+}
+
+func (me *prettyPrinter) result(result contracts.Result, width int) {
+	comment := result.Comment
+	lineNumber := fmt.Sprintf("%*d", width, comment.LineNumber)
+	gutter := strings.Repeat(" ", width)
+
+	me.printf(
+		"  %s  %s  %s %s\n",
+		me.paint("dim", fmt.Sprintf("%d:%d", comment.LineNumber, comment.Column)),
+		me.paint("red", string(result.Violation.Severity)),
+		result.Violation.Message,
+		me.paint("gray", fmt.Sprintf("(%s)", result.Violation.Rule)),
+	)
+	line, padding := snippet(comment)
+	me.printf("    %s %s %s\n", me.paint("blue", lineNumber), me.paint("blue", "|"), line)
+	me.printf("    %s %s %s%s\n", gutter, me.paint("blue", "|"), padding, me.paint("red", "^"))
+	if comment.Blame != nil {
+		me.printf("    %s %s %s\n", gutter, me.paint("blue", "="), me.paint("dim", fmt.Sprintf("last changed by %s", describeBlame(comment.Blame))))
+	}
+}
+
+func (me *prettyPrinter) summary(report contracts.Report, byFile utils.OrderedMap[string, []contracts.Result]) {
+	perRule := map[contracts.Rule]int{}
+	severities := map[contracts.Rule]contracts.Severity{}
+	total := 0
+	filesWithIssues := 0
+	for _, entry := range byFile {
+		if len(entry.Value) > 0 {
+			filesWithIssues += 1
+		}
+		for _, result := range entry.Value {
+			perRule[result.Violation.Rule] += 1
+			severities[result.Violation.Rule] = result.Violation.Severity
+			total += 1
+		}
+	}
+
+	if len(perRule) > 0 {
+		ruleNames := make([]string, 0, len(perRule))
+		for rule := range perRule {
+			ruleNames = append(ruleNames, string(rule))
+		}
+		sort.Strings(ruleNames)
+
+		table := tabwriter.NewWriter(me.out, 0, 4, 2, ' ', 0)
+		_, me.err = fmt.Fprintln(table, "Rule\tSeverity\tCount")
+		for _, name := range ruleNames {
+			rule := contracts.Rule(name)
+			if me.err == nil {
+				_, me.err = fmt.Fprintf(table, "%s\t%s\t%d\n", rule, severities[rule], perRule[rule])
+			}
+		}
+		if me.err == nil {
+			me.err = table.Flush()
+		}
+		me.printf("\n")
+	}
+
+	line := fmt.Sprintf("%s in %d of %s", plural(total, "issue"), filesWithIssues, plural(len(report.Files), "file"))
+	if len(report.Errors) > 0 {
+		line += fmt.Sprintf(", %s could not be processed", plural(len(report.Errors), "file"))
+	}
+	color := "green"
+	if total > 0 || len(report.Errors) > 0 {
+		color = "red"
+	}
+	me.printf("%s\n", me.paint(color, me.paint("bold", line)))
+}
+
+func newPrettyFormatter(config contracts.ReporterConfig) (formatter, error) {
+	return func(out io.Writer, report contracts.Report) error {
+		printer := &prettyPrinter{
+			out:   out,
+			color: config.Color,
+		}
+
+		byFile := violationsByFile(report)
+		for _, entry := range byFile {
+			if len(entry.Value) == 0 {
+				continue
+			}
+			width := 0
+			for _, result := range entry.Value {
+				width = utils.Max(width, len(fmt.Sprintf("%d", result.Comment.LineNumber)))
+			}
+			printer.printf("%s\n", printer.paint("bold", entry.Key))
+			for _, result := range entry.Value {
+				printer.result(result, width)
+			}
+			printer.printf("\n")
+		}
+		for _, entry := range utils.SortedMap(report.Errors) {
+			printer.printf("%s\n", printer.paint("bold", entry.Key))
+			printer.printf("  %s  %s\n\n", printer.paint("red", string(contracts.SeverityError)), entry.Value.Error())
+		}
+
+		printer.summary(report, byFile)
+		return printer.err
+	}, nil
+}
@@ -0,0 +1,65 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+)
+
+type formatter func(out io.Writer, report contracts.Report) error
+
+type formatterFactory func(config contracts.ReporterConfig) (formatter, error)
+
+func static(format formatter) formatterFactory {
+	return func(config contracts.ReporterConfig) (formatter, error) {
+		return format, nil
+	}
+}
+
+var formatters = map[contracts.Format]formatterFactory{
+	contracts.FormatText:       static(formatText),
+	contracts.FormatPretty:     newPrettyFormatter,
+	contracts.FormatJSON:       static(formatJSON),
+	contracts.FormatNDJSON:     static(formatNDJSON),
+	contracts.FormatSARIF:      static(formatSARIF),
+	contracts.FormatJUnit:      static(formatJUnit),
+	contracts.FormatCheckstyle: static(formatCheckstyle),
+	contracts.FormatGitHub:     static(formatGitHub),
+	contracts.FormatGitLab:     static(formatGitLab),
+	contracts.FormatTemplate:   newTemplateFormatter,
+	contracts.FormatHTML:       newHTMLFormatter,
+	contracts.FormatMarkdown:   static(formatMarkdown),
+	contracts.FormatCSV:        newCSVFormatter,
+	contracts.FormatICal:       newICalFormatter,
+	contracts.FormatTable:      newTableFormatter,
+}
+
+type reporter struct {
+	contracts.ReporterConfig
+	logger *logrus.Logger
+	format formatter
+}
+
+func New(logger *logrus.Logger, config contracts.ReporterConfig) (contracts.Reporter, error) {
+	factory, found := formatters[config.Format]
+	if !found {
+		return nil, fmt.Errorf("unknown format %q", config.Format)
+	}
+	format, err := factory(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s format: %w", config.Format, err)
+	}
+
+	return &reporter{
+		ReporterConfig: config,
+		logger:         logger,
+		format:         format,
+	}, nil
+}
+
+func (me *reporter) Report(report contracts.Report) error {
+	me.logger.Debugf("reporting %d results as %s", len(report.Results), me.Format)
+	return me.format(me.Output, report)
+}
@@ -0,0 +1,546 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func fixtureReport() contracts.Report {
+	return contracts.Report{
+		Files: []string{"src/clean.c", "src/main.c"},
+		Results: []contracts.Result{
+			{
+				Path: "src/main.c",
+				Comment: contracts.ParsedComment{
+					CommentPrefix: "@",
+					Prefix:        "TODO",
+					Owner:         "alice",
+					Content:       "implement later",
+					Expiry:        utils.Pointerize(utils.Must(time.Parse("2006-01-02", "2022-06-15"))),
+					LineNumber:    4,
+					Column:        3,
+					OriginalLine:  "@TODO(alice)[2022-06-15]: implement later",
+					SourceLine:    "\t @TODO(alice)[2022-06-15]: implement later",
+				},
+				Violation: &contracts.Violation{
+					Rule:     contracts.RuleOverdue,
+					Severity: contracts.SeverityError,
+					Message:  "TODO now overdue for 4 days",
+				},
+				Status:     contracts.StatusOverdue,
+				CodeOwners: []string{"@org/team-payments", "@alice"},
+			},
+			{
+				Path: "src/main.c",
+				Comment: contracts.ParsedComment{
+					CommentPrefix: "%",
+					Prefix:        "FIXME",
+					Content:       `what "status", code?`,
+					LineNumber:    12,
+					Column:        1,
+					OriginalLine:  `%FIXME: what "status", code?`,
+					Blame: &contracts.Blame{
+						Commit:      "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b",
+						Author:      "bob",
+						AuthorEmail: "bob@example.com",
+						Date:        utils.Must(time.Parse("2006-01-02", "2022-01-04")),
+					},
+				},
+				Violation: &contracts.Violation{
+					Rule:     contracts.RuleMissingExpiry,
+					Severity: contracts.SeverityError,
+					Message:  "FIXME missing expiry date",
+				},
+				Status: contracts.StatusUndated,
+			},
+		},
+		Errors: contracts.FilesErrors{
+			"src/broken.c": fmt.Errorf("failed to process src/broken.c: permission denied"),
+		},
+		Summary: contracts.Summary{
+			Files:      2,
+			Comments:   3,
+			Violations: 2,
+			Errors:     1,
+		},
+	}
+}
+
+// inventoryReport extends fixtureReport with comments which are not violations, as used by inventory formats
+func inventoryReport() contracts.Report {
+	report := fixtureReport()
+	report.Results = append(report.Results,
+		contracts.Result{
+			Path: "lib/util/strings.c",
+			Comment: contracts.ParsedComment{
+				CommentPrefix: "@",
+				Prefix:        "TODO",
+				Owner:         "bob",
+				Content:       "use <string.h> & co",
+				Expiry:        utils.Pointerize(utils.Must(time.Parse("2006-01-02", "2022-06-25"))),
+				LineNumber:    7,
+				Column:        1,
+				OriginalLine:  "@TODO(bob)[2022-06-25]: use <string.h> & co",
+			},
+			Status: contracts.StatusWarning,
+		},
+		contracts.Result{
+			Path: "README.md",
+			Comment: contracts.ParsedComment{
+				CommentPrefix: "@",
+				Prefix:        "TODO",
+				Content:       "document everything",
+				Expiry:        utils.Pointerize(utils.Must(time.Parse("2006-01-02", "2022-09-01"))),
+				LineNumber:    1,
+				Column:        1,
+				OriginalLine:  "@TODO[2022-09-01]: document everything",
+			},
+			Status: contracts.StatusOK,
+		},
+	)
+	report.Files = append(report.Files, "README.md", "lib/util/strings.c")
+	report.Summary.Files += 2
+	report.Summary.Comments += 2
+	return report
+}
+
+func Test_New(t *testing.T) {
+	_, err := New(logrus.New(), contracts.ReporterConfig{
+		Format: "unknown",
+	})
+	assert.Error(t, err)
+}
+
+func Test_Report(t *testing.T) {
+	tests := []struct {
+		name   string
+		format contracts.Format
+		want   string
+	}{
+		{
+			name:   "text",
+			format: contracts.FormatText,
+			want: `src/main.c:4 TODO now overdue for 4 days
+src/main.c:12 FIXME missing expiry date (last changed by bob <bob@example.com> in 1a2b3c4d on 2022-01-04)
+src/broken.c failed to process src/broken.c: permission denied
+`,
+		},
+		{
+			name:   "json",
+			format: contracts.FormatJSON,
+			want: `{
+  "results": [
+    {
+      "path": "src/main.c",
+      "line": 4,
+      "column": 3,
+      "comment_prefix": "@",
+      "prefix": "TODO",
+      "content": "implement later",
+      "expiry": "2022-06-15",
+      "owner": "alice",
+      "severity": "error",
+      "rule": "overdue",
+      "message": "TODO now overdue for 4 days",
+      "code_owners": [
+        "@org/team-payments",
+        "@alice"
+      ]
+    },
+    {
+      "path": "src/main.c",
+      "line": 12,
+      "column": 1,
+      "comment_prefix": "%",
+      "prefix": "FIXME",
+      "content": "what \"status\", code?",
+      "expiry": null,
+      "severity": "error",
+      "rule": "missing-expiry",
+      "message": "FIXME missing expiry date",
+      "blame": {
+        "commit": "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b",
+        "author": "bob",
+        "author_email": "bob@example.com",
+        "date": "2022-01-04"
+      }
+    }
+  ],
+  "errors": [
+    {
+      "path": "src/broken.c",
+      "error": "failed to process src/broken.c: permission denied"
+    }
+  ],
+  "summary": {
+    "files": 2,
+    "comments": 3,
+    "violations": 2,
+    "errors": 1
+  }
+}
+`,
+		},
+		{
+			name:   "ndjson",
+			format: contracts.FormatNDJSON,
+			want: `{"type":"result","path":"src/main.c","line":4,"column":3,"comment_prefix":"@","prefix":"TODO","content":"implement later","expiry":"2022-06-15","owner":"alice","severity":"error","rule":"overdue","message":"TODO now overdue for 4 days","code_owners":["@org/team-payments","@alice"]}
+{"type":"result","path":"src/main.c","line":12,"column":1,"comment_prefix":"%","prefix":"FIXME","content":"what \"status\", code?","expiry":null,"severity":"error","rule":"missing-expiry","message":"FIXME missing expiry date","blame":{"commit":"1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b","author":"bob","author_email":"bob@example.com","date":"2022-01-04"}}
+{"type":"error","path":"src/broken.c","error":"failed to process src/broken.c: permission denied"}
+{"type":"summary","files":2,"comments":3,"violations":2,"errors":1}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			me, err := New(logrus.New(), contracts.ReporterConfig{
+				Format: tt.format,
+				Output: out,
+			})
+			if err != nil {
+				t.Fatalf("failed to create reporter: %v", err)
+			}
+
+			err = me.Report(fixtureReport())
+			if err != nil {
+				t.Fatalf("failed to report: %v", err)
+			}
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func Test_Report_sarif(t *testing.T) {
+	render := func(report contracts.Report) sarifLog {
+		out := &bytes.Buffer{}
+		me, err := New(logrus.New(), contracts.ReporterConfig{
+			Format: contracts.FormatSARIF,
+			Output: out,
+		})
+		if err != nil {
+			t.Fatalf("failed to create reporter: %v", err)
+		}
+		err = me.Report(report)
+		if err != nil {
+			t.Fatalf("failed to report: %v", err)
+		}
+		var log sarifLog
+		err = json.Unmarshal(out.Bytes(), &log)
+		if err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return log
+	}
+
+	log := render(fixtureReport())
+	assert.Equal(t, "2.1.0", log.Version)
+	if !assert.Len(t, log.Runs, 1) {
+		return
+	}
+	run := log.Runs[0]
+	assert.Equal(t, []string{"overdue", "missing-expiry", "invalid-date", "snooze-limit", "invalid-suppression", "unused-suppression"}, utils.MapSlice(run.Tool.Driver.Rules, func(rule sarifRule) string {
+		return rule.ID
+	}))
+	assert.False(t, run.Invocations[0].ExecutionSuccessful)
+	assert.Len(t, run.Invocations[0].ToolExecutionNotifications, 1)
+	if !assert.Len(t, run.Results, 2) {
+		return
+	}
+	assert.Equal(t, "missing-expiry", run.Results[1].RuleID)
+	assert.Equal(t, 1, run.Results[1].RuleIndex)
+	assert.Equal(t, "src/main.c", run.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, &sarifRegion{StartLine: 12, StartColumn: 1}, run.Results[1].Locations[0].PhysicalLocation.Region)
+	if assert.NotNil(t, run.Results[0].Properties) {
+		assert.Nil(t, run.Results[0].Properties.Blame)
+		assert.Equal(t, []string{"@org/team-payments", "@alice"}, run.Results[0].Properties.CodeOwners)
+	}
+	if assert.NotNil(t, run.Results[1].Properties) {
+		assert.Equal(t, "bob@example.com", run.Results[1].Properties.Blame.AuthorEmail)
+	}
+
+	moved := fixtureReport()
+	moved.Results[0].Comment.LineNumber += 10
+	moved.Results[1].Comment.LineNumber += 3
+	movedLog := render(moved)
+	for i := range run.Results {
+		assert.Equal(t, run.Results[i].PartialFingerprints, movedLog.Runs[0].Results[i].PartialFingerprints)
+	}
+	assert.NotEqual(t, run.Results[0].PartialFingerprints, run.Results[1].PartialFingerprints)
+}
+
+func Test_Report_golden(t *testing.T) {
+	tests := []struct {
+		format contracts.Format
+		color  bool
+		report func() contracts.Report
+		golden string
+	}{
+		{
+			format: contracts.FormatPretty,
+			golden: "pretty.txt",
+		},
+		{
+			format: contracts.FormatPretty,
+			color:  true,
+			golden: "pretty-color.txt",
+		},
+		{
+			format: contracts.FormatJUnit,
+			golden: "junit.xml",
+		},
+		{
+			format: contracts.FormatCheckstyle,
+			golden: "checkstyle.xml",
+		},
+		{
+			format: contracts.FormatGitHub,
+			golden: "github.txt",
+		},
+		{
+			format: contracts.FormatGitLab,
+			golden: "gitlab.json",
+		},
+		{
+			format: contracts.FormatMarkdown,
+			golden: "summary.md",
+		},
+		{
+			format: contracts.FormatCSV,
+			report: inventoryReport,
+			golden: "inventory.csv",
+		},
+		{
+			format: contracts.FormatICal,
+			report: inventoryReport,
+			golden: "calendar.ics",
+		},
+		{
+			format: contracts.FormatTable,
+			report: inventoryReport,
+			golden: "inventory.txt",
+		},
+		{
+			format: contracts.FormatTable,
+			color:  true,
+			report: inventoryReport,
+			golden: "inventory-color.txt",
+		},
+		{
+			format: contracts.FormatHTML,
+			report: inventoryReport,
+			golden: "report.html",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			me, err := New(logrus.New(), contracts.ReporterConfig{
+				Format: tt.format,
+				Output: out,
+				Color:  tt.color,
+				Now:    utils.Must(time.Parse("2006-01-02", "2022-06-19")),
+			})
+			if err != nil {
+				t.Fatalf("failed to create reporter: %v", err)
+			}
+
+			report := fixtureReport
+			if tt.report != nil {
+				report = tt.report
+			}
+			err = me.Report(report())
+			if err != nil {
+				t.Fatalf("failed to report: %v", err)
+			}
+
+			golden := filepath.Join("testdata", tt.golden)
+			if *update {
+				err = os.WriteFile(golden, out.Bytes(), 0o644)
+				if err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			assert.Equal(t, string(want), out.String())
+		})
+	}
+}
+
+func Test_Report_template(t *testing.T) {
+	now := utils.Must(time.Parse("2006-01-02", "2022-06-19"))
+
+	tests := []struct {
+		name    string
+		config  contracts.ReporterConfig
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "works",
+			config: contracts.ReporterConfig{
+				Template: "{{.Path}}:{{.Line}}:{{.Column}}: {{.Message}} [{{.Rule}}]",
+			},
+			want: `src/main.c:4:3: TODO now overdue for 4 days [overdue]
+src/main.c:12:1: FIXME missing expiry date [missing-expiry]
+src/broken.c:0:0: failed to process src/broken.c: permission denied []
+`,
+		},
+		{
+			name: "works with helpers",
+			config: contracts.ReporterConfig{
+				Template: `{{if not .Error}}{{.Comment.Prefix}}({{.Owner}}) {{date "02/01/2006" .Expiry}} {{relative .Expiry}} {{color "red" .Severity}}{{end}}`,
+				Now:      now,
+			},
+			want: `TODO(alice) 15/06/2022 4 days ago error
+FIXME()   error
+`,
+		},
+		{
+			name: "works with colors",
+			config: contracts.ReporterConfig{
+				Template: `{{if not .Error}}{{color "bold" .Path}} {{relative .Expiry}}{{end}}`,
+				Now:      now.Add(-10 * 24 * time.Hour),
+				Color:    true,
+			},
+			want: "\x1b[1msrc/main.c\x1b[0m in 6 days\n\x1b[1msrc/main.c\x1b[0m \n",
+		},
+		{
+			name: "fails with an unknown color",
+			config: contracts.ReporterConfig{
+				Template: `{{color "pink" .Path}}`,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "fails without a template",
+			config:  contracts.ReporterConfig{},
+			wantErr: true,
+		},
+		{
+			name: "fails with an invalid template",
+			config: contracts.ReporterConfig{
+				Template: "{{.Path",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			tt.config.Format = contracts.FormatTemplate
+			tt.config.Output = out
+			me, err := New(logrus.New(), tt.config)
+			if err == nil {
+				err = me.Report(fixtureReport())
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, out.String())
+			}
+		})
+	}
+}
+
+func Test_Report_markdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		report contracts.Report
+		want   string
+	}{
+		{
+			name: "works without issues",
+			report: contracts.Report{
+				Files: []string{"src/clean.c"},
+			},
+			want: "### gofixit\n\nNo issues found in 1 file.\n",
+		},
+		{
+			name: "works with special characters",
+			report: contracts.Report{
+				Files: []string{"src/a|b.c"},
+				Results: []contracts.Result{
+					{
+						Path:    "src/a|b.c",
+						Comment: contracts.ParsedComment{LineNumber: 2},
+						Violation: &contracts.Violation{
+							Rule:     contracts.RuleInvalidDate,
+							Severity: contracts.SeverityError,
+							Message:  `TODO has an invalid expiry date "a|*b*"`,
+						},
+					},
+				},
+			},
+			want: "### gofixit\n\n" +
+				"| Severity | Count |\n| --- | ---: |\n| error | 1 |\n\n" +
+				"| Location | Severity | Rule | Message | Last change |\n| --- | --- | --- | --- | --- |\n" +
+				"| `src/a\\|b.c:2` | error | invalid-date | TODO has an invalid expiry date \"a\\|\\*b\\*\" | - |\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			me, err := New(logrus.New(), contracts.ReporterConfig{
+				Format: contracts.FormatMarkdown,
+				Output: out,
+			})
+			if err != nil {
+				t.Fatalf("failed to create reporter: %v", err)
+			}
+
+			err = me.Report(tt.report)
+			if err != nil {
+				t.Fatalf("failed to report: %v", err)
+			}
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func Test_icalFold(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "short",
+			line: "SUMMARY:short",
+			want: "SUMMARY:short",
+		},
+		{
+			name: "long",
+			line: "SUMMARY:" + strings.Repeat("a", 100),
+			want: "SUMMARY:" + strings.Repeat("a", 67) + "\r\n " + strings.Repeat("a", 33),
+		},
+		{
+			name: "multi-byte",
+			line: "SUMMARY:" + strings.Repeat("a", 66) + "éé",
+			want: "SUMMARY:" + strings.Repeat("a", 66) + "\r\n éé",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, icalFold(tt.line))
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package reporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+)
+
+type ruleDescription struct {
+	rule        contracts.Rule
+	name        string
+	description string
+}
+
+var rules = []ruleDescription{
+	{
+		rule:        contracts.RuleOverdue,
+		name:        "OverdueComment",
+		description: "The expiry date of the comment has passed",
+	},
+	{
+		rule:        contracts.RuleMissingExpiry,
+		name:        "MissingExpiry",
+		description: "The comment has no expiry date (strict mode)",
+	},
+	{
+		rule:        contracts.RuleInvalidDate,
+		name:        "InvalidDate",
+		description: "The expiry date of the comment does not match the date layout",
+	},
+	{
+		rule:        contracts.RuleSnoozeLimit,
+		name:        "SnoozeLimit",
+		description: "The comment was snoozed more times than allowed",
+	},
+	{
+		rule:        contracts.RuleInvalidSuppression,
+		name:        "InvalidSuppression",
+		description: "The gofixit suppression directive is malformed or has no reason",
+	},
+	{
+		rule:        contracts.RuleUnusedSuppression,
+		name:        "UnusedSuppression",
+		description: "The gofixit suppression directive does not suppress any violation",
+	},
+}
+
+// fingerprints generates an identifier for each result which stays the same when lines are moved around,
+// identical comments in the same file are told apart by their order
+func fingerprints(results []contracts.Result) []string {
+	seen := map[string]int{}
+	out := make([]string, 0, len(results))
+	for _, result := range results {
+		rule := contracts.Rule("")
+		if result.Violation != nil {
+			rule = result.Violation.Rule
+		}
+		key := strings.Join([]string{
+			string(rule),
+			result.Path,
+			result.Comment.Prefix,
+			strings.TrimSpace(result.Comment.Content),
+		}, "\x00")
+		hash := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", key, seen[key])))
+		seen[key] += 1
+		out = append(out, hex.EncodeToString(hash[:]))
+	}
+	return out
+}
@@ -0,0 +1,203 @@
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path/filepath"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+)
+
+const (
+	sarifVersion        = "2.1.0"
+	sarifSchema         = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifFingerprintKey = "gofixit/v1"
+	toolName            = "gofixit"
+	toolURI             = "https://github.com/LouisBrunner/gofixit"
+)
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRegion struct {
+	StartLine   uint `json:"startLine"`
+	StartColumn uint `json:"startColumn,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          *sarifProperties  `json:"properties,omitempty"`
+}
+
+type sarifProperties struct {
+	Blame      *jsonBlame `json:"blame,omitempty"`
+	CodeOwners []string   `json:"codeOwners,omitempty"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+func sarifLevel(severity contracts.Severity) string {
+	switch severity {
+	case contracts.SeverityError:
+		return "error"
+	}
+	return "warning"
+}
+
+func sarifURI(path string) string {
+	path = filepath.ToSlash(path)
+	if filepath.IsAbs(path) {
+		return (&url.URL{Scheme: "file", Path: path}).String()
+	}
+	return (&url.URL{Path: path}).String()
+}
+
+func formatSARIF(out io.Writer, report contracts.Report) error {
+	ruleIndexes := make(map[contracts.Rule]int, len(rules))
+	sarifRules := make([]sarifRule, 0, len(rules))
+	for i, rule := range rules {
+		ruleIndexes[rule.rule] = i
+		sarifRules = append(sarifRules, sarifRule{
+			ID:               string(rule.rule),
+			Name:             rule.name,
+			ShortDescription: sarifMessage{Text: rule.description},
+			DefaultConfiguration: sarifConfiguration{
+				Level: sarifLevel(contracts.SeverityError),
+			},
+		})
+	}
+
+	violations := []contracts.Result{}
+	for _, result := range report.Results {
+		if result.Violation != nil {
+			violations = append(violations, result)
+		}
+	}
+	hashes := fingerprints(violations)
+
+	results := make([]sarifResult, 0, len(violations))
+	for i, result := range violations {
+		var properties *sarifProperties
+		if result.Comment.Blame != nil || len(result.CodeOwners) > 0 {
+			properties = &sarifProperties{
+				Blame:      toJSONBlame(result.Comment.Blame),
+				CodeOwners: result.CodeOwners,
+			}
+		}
+		results = append(results, sarifResult{
+			RuleID:    string(result.Violation.Rule),
+			RuleIndex: ruleIndexes[result.Violation.Rule],
+			Level:     sarifLevel(result.Violation.Severity),
+			Message:   sarifMessage{Text: result.Violation.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: sarifURI(result.Path)},
+					Region: &sarifRegion{
+						StartLine:   result.Comment.LineNumber,
+						StartColumn: result.Comment.Column,
+					},
+				},
+			}},
+			PartialFingerprints: map[string]string{
+				sarifFingerprintKey: hashes[i],
+			},
+			Properties: properties,
+		})
+	}
+
+	notifications := utils.MapSlice(utils.SortedMap(report.Errors), func(entry utils.MapEntry[string, error]) sarifNotification {
+		return sarifNotification{
+			Level:   "error",
+			Message: sarifMessage{Text: entry.Value.Error()},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: sarifURI(entry.Key)},
+				},
+			}},
+		}
+	})
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{
+				Driver: sarifDriver{
+					Name:           toolName,
+					InformationURI: toolURI,
+					Rules:          sarifRules,
+				},
+			},
+			Invocations: []sarifInvocation{{
+				ExecutionSuccessful:        len(notifications) == 0,
+				ToolExecutionNotifications: notifications,
+			}},
+			Results: results,
+		}},
+	})
+}
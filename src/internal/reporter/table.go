@@ -0,0 +1,96 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+)
+
+func statusColor(status contracts.Status) string {
+	switch status {
+	case contracts.StatusOverdue, contracts.StatusInvalid:
+		return "red"
+	case contracts.StatusWarning:
+		return "yellow"
+	case contracts.StatusOK:
+		return "green"
+	}
+	return "gray"
+}
+
+func newTableFormatter(config contracts.ReporterConfig) (formatter, error) {
+	return func(out io.Writer, report contracts.Report) error {
+		// statuses are colored once aligned, as escape sequences would throw off the column widths
+		rendered := &strings.Builder{}
+		table := tabwriter.NewWriter(rendered, 0, 4, 2, ' ', 0)
+		_, err := fmt.Fprintln(table, "STATUS\tEXPIRY\tREMAINING\tLOCATION\tOWNER\tLAST CHANGE\tCOMMENT")
+		if err != nil {
+			return err
+		}
+		for _, result := range report.Results {
+			comment := result.Comment
+			expiry, remaining := "-", "-"
+			if comment.InvalidExpiry != "" {
+				expiry = comment.InvalidExpiry
+			}
+			if comment.Expiry != nil {
+				expiry = comment.Expiry.Format(jsonDateLayout)
+				remaining, err = relativeTime(config.Now, comment.Expiry)
+				if err != nil {
+					return err
+				}
+			}
+			owner := comment.Owner
+			if owner == "" {
+				owner = "-"
+			}
+			change := "-"
+			if comment.Blame != nil {
+				change = fmt.Sprintf("%s %s", comment.Blame.Date.Format(jsonDateLayout), comment.Blame.Author)
+			}
+			_, err = fmt.Fprintf(
+				table,
+				"%s\t%s\t%s\t%s:%d\t%s\t%s\t%s\n",
+				result.Status,
+				expiry,
+				remaining,
+				result.Path,
+				comment.LineNumber,
+				owner,
+				change,
+				strings.TrimSpace(fmt.Sprintf("%s %s", comment.Prefix, strings.TrimSpace(comment.Content))),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		err = table.Flush()
+		if err != nil {
+			return err
+		}
+		lines := strings.SplitAfter(rendered.String(), "\n")
+		for i, result := range report.Results {
+			status := string(result.Status)
+			painted, err := colorize(config.Color, statusColor(result.Status), status)
+			if err != nil {
+				return err
+			}
+			lines[i+1] = painted + strings.TrimPrefix(lines[i+1], status)
+		}
+		_, err = io.WriteString(out, strings.Join(lines, ""))
+		if err != nil {
+			return err
+		}
+		for _, entry := range utils.SortedMap(report.Errors) {
+			_, err := fmt.Fprintf(out, "%s %s\n", entry.Key, entry.Value.Error())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
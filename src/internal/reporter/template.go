@@ -0,0 +1,137 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+	"github.com/hako/durafmt"
+)
+
+// templateResult is the data available to user templates, one per result or file error
+type templateResult struct {
+	Path     string
+	Line     uint
+	Column   uint
+	Message  string
+	Rule     contracts.Rule
+	Severity contracts.Severity
+	Prefix   string
+	Owner    string
+	Content  string
+	Expiry   *time.Time
+	Status   contracts.Status
+	Blame    *contracts.Blame
+	// CodeOwners lists the owners of the file according to CODEOWNERS
+	CodeOwners []string
+	Comment    contracts.ParsedComment
+	Violation  *contracts.Violation
+	Error      error
+}
+
+func toTime(value any) (*time.Time, error) {
+	switch typed := value.(type) {
+	case time.Time:
+		return &typed, nil
+	case *time.Time:
+		return typed, nil
+	case nil:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("expected a time, got %T", value)
+}
+
+func relativeTime(now time.Time, value any) (string, error) {
+	when, err := toTime(value)
+	if err != nil || when == nil {
+		return "", err
+	}
+	duration := when.Sub(now)
+	if duration < 0 {
+		return fmt.Sprintf("%s ago", durafmt.Parse(-duration).LimitFirstN(2)), nil
+	}
+	return fmt.Sprintf("in %s", durafmt.Parse(duration).LimitFirstN(2)), nil
+}
+
+func templateFuncs(config contracts.ReporterConfig) template.FuncMap {
+	return template.FuncMap{
+		"relative": func(value any) (string, error) {
+			return relativeTime(config.Now, value)
+		},
+		"date": func(layout string, value any) (string, error) {
+			when, err := toTime(value)
+			if err != nil || when == nil {
+				return "", err
+			}
+			return when.Format(layout), nil
+		},
+		"color": func(name string, text any) (string, error) {
+			return colorize(config.Color, name, fmt.Sprint(text))
+		},
+	}
+}
+
+func newTemplateFormatter(config contracts.ReporterConfig) (formatter, error) {
+	if config.Template == "" {
+		return nil, fmt.Errorf("missing template")
+	}
+	tmpl, err := template.New("result").Funcs(templateFuncs(config)).Parse(config.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	// results rendering to nothing are skipped, so templates can be used to filter
+	execute := func(out io.Writer, data templateResult) error {
+		rendered := &strings.Builder{}
+		err := tmpl.Execute(rendered, data)
+		if err != nil || rendered.Len() == 0 {
+			return err
+		}
+		_, err = fmt.Fprintln(out, rendered.String())
+		return err
+	}
+
+	return func(out io.Writer, report contracts.Report) error {
+		for _, result := range report.Results {
+			data := templateResult{
+				Path:       result.Path,
+				Line:       result.Comment.LineNumber,
+				Column:     result.Comment.Column,
+				Prefix:     result.Comment.Prefix,
+				Owner:      result.Comment.Owner,
+				Content:    result.Comment.Content,
+				Expiry:     result.Comment.Expiry,
+				Status:     result.Status,
+				Blame:      result.Comment.Blame,
+				CodeOwners: result.CodeOwners,
+				Comment:    result.Comment,
+				Violation:  result.Violation,
+			}
+			if result.Violation != nil {
+				data.Message = result.Violation.Message
+				data.Rule = result.Violation.Rule
+				data.Severity = result.Violation.Severity
+			}
+			err := execute(out, data)
+			if err != nil {
+				return err
+			}
+		}
+		for _, entry := range utils.SortedMap(report.Errors) {
+			err := execute(out, templateResult{
+				Path:     entry.Key,
+				Message:  entry.Value.Error(),
+				Severity: contracts.SeverityError,
+				Error:    entry.Value,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,28 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+)
+
+func formatText(out io.Writer, report contracts.Report) error {
+	for _, result := range report.Results {
+		if result.Violation == nil {
+			continue
+		}
+		_, err := fmt.Fprintf(out, "%s:%d %s\n", result.Path, result.Comment.LineNumber, withBlame(result.Violation.Message, result.Comment))
+		if err != nil {
+			return err
+		}
+	}
+	for _, entry := range utils.SortedMap(report.Errors) {
+		_, err := fmt.Fprintf(out, "%s %s\n", entry.Key, entry.Value.Error())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+)
+
+const (
+	checkstyleVersion = "8.0"
+	checkstyleSource  = "gofixit"
+)
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+	Errors    []junitFailure `xml:"error"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type checkstyleError struct {
+	Line     uint   `xml:"line,attr,omitempty"`
+	Column   uint   `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+func writeXML(out io.Writer, document any) error {
+	_, err := io.WriteString(out, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(out)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(document)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(out, "\n")
+	return err
+}
+
+// violationsByFile groups the violations of a report by path, including files without any
+func violationsByFile(report contracts.Report) utils.OrderedMap[string, []contracts.Result] {
+	byFile := make(map[string][]contracts.Result, len(report.Files))
+	for _, path := range report.Files {
+		byFile[path] = []contracts.Result{}
+	}
+	for _, result := range report.Results {
+		if result.Violation == nil {
+			continue
+		}
+		byFile[result.Path] = append(byFile[result.Path], result)
+	}
+	return utils.SortedMap(byFile)
+}
+
+func formatJUnit(out io.Writer, report contracts.Report) error {
+	suite := junitTestSuite{
+		Name:      toolName,
+		TestCases: []junitTestCase{},
+	}
+	for _, entry := range violationsByFile(report) {
+		testCase := junitTestCase{
+			Name:      entry.Key,
+			ClassName: toolName,
+		}
+		for _, result := range entry.Value {
+			testCase.Failures = append(testCase.Failures, junitFailure{
+				Message: withBlame(result.Violation.Message, result.Comment),
+				Type:    string(result.Violation.Rule),
+				Content: fmt.Sprintf("%s:%d:%d: %s", result.Path, result.Comment.LineNumber, result.Comment.Column, result.Comment.OriginalLine),
+			})
+		}
+		suite.Failures += len(testCase.Failures)
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	for _, entry := range utils.SortedMap(report.Errors) {
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      entry.Key,
+			ClassName: toolName,
+			Errors: []junitFailure{{
+				Message: entry.Value.Error(),
+				Type:    "error",
+			}},
+		})
+		suite.Errors += 1
+	}
+	suite.Tests = len(suite.TestCases)
+
+	return writeXML(out, junitTestSuites{
+		Suites: []junitTestSuite{suite},
+	})
+}
+
+func formatCheckstyle(out io.Writer, report contracts.Report) error {
+	document := checkstyleReport{
+		Version: checkstyleVersion,
+		Files:   []checkstyleFile{},
+	}
+	errors := make(map[string]checkstyleFile, len(report.Files)+len(report.Errors))
+	for _, entry := range violationsByFile(report) {
+		errors[entry.Key] = checkstyleFile{
+			Name: entry.Key,
+			Errors: utils.MapSlice(entry.Value, func(result contracts.Result) checkstyleError {
+				return checkstyleError{
+					Line:     result.Comment.LineNumber,
+					Column:   result.Comment.Column,
+					Severity: string(result.Violation.Severity),
+					Message:  withBlame(result.Violation.Message, result.Comment),
+					Source:   fmt.Sprintf("%s.%s", checkstyleSource, result.Violation.Rule),
+				}
+			}),
+		}
+	}
+	for _, entry := range utils.SortedMap(report.Errors) {
+		errors[entry.Key] = checkstyleFile{
+			Name: entry.Key,
+			Errors: []checkstyleError{{
+				Severity: string(contracts.SeverityError),
+				Message:  entry.Value.Error(),
+				Source:   fmt.Sprintf("%s.error", checkstyleSource),
+			}},
+		}
+	}
+	for _, entry := range utils.SortedMap(errors) {
+		document.Files = append(document.Files, entry.Value)
+	}
+
+	return writeXML(out, document)
+}
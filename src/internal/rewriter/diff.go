@@ -0,0 +1,74 @@
+package rewriter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/LouisBrunner/gofixit/src/utils"
+)
+
+const diffContext = 3
+
+type hunk struct {
+	start int
+	end   int
+}
+
+// hunks groups changed lines with their context, merging groups which touch each other
+func hunks(total int, changed []int) []hunk {
+	groups := []hunk{}
+	for _, idx := range changed {
+		start, end := utils.Max(idx-diffContext, 0), utils.Min(idx+diffContext+1, total)
+		if len(groups) > 0 && start <= groups[len(groups)-1].end {
+			groups[len(groups)-1].end = end
+			continue
+		}
+		groups = append(groups, hunk{start: start, end: end})
+	}
+	return groups
+}
+
+func diffLine(out *strings.Builder, marker string, line string) {
+	out.WriteString(marker)
+	out.WriteString(strings.TrimSuffix(line, "\n"))
+	out.WriteString("\n")
+	if !strings.HasSuffix(line, "\n") {
+		out.WriteString("\\ No newline at end of file\n")
+	}
+}
+
+// writeDiff prints a unified diff between two versions of a file which only differ by replaced lines
+func writeDiff(out io.Writer, filepath string, before, after []string, changed []int) error {
+	isChanged := make(map[int]bool, len(changed))
+	for _, idx := range changed {
+		isChanged[idx] = true
+	}
+
+	diff := &strings.Builder{}
+	fmt.Fprintf(diff, "--- a/%s\n+++ b/%s\n", filepath, filepath)
+	for _, group := range hunks(len(before), changed) {
+		count := group.end - group.start
+		fmt.Fprintf(diff, "@@ -%d,%d +%d,%d @@\n", group.start+1, count, group.start+1, count)
+		for idx := group.start; idx < group.end; {
+			if !isChanged[idx] {
+				diffLine(diff, " ", before[idx])
+				idx += 1
+				continue
+			}
+			run := idx
+			for run < group.end && isChanged[run] {
+				run += 1
+			}
+			for i := idx; i < run; i++ {
+				diffLine(diff, "-", before[i])
+			}
+			for i := idx; i < run; i++ {
+				diffLine(diff, "+", after[i])
+			}
+			idx = run
+		}
+	}
+	_, err := io.WriteString(out, diff.String())
+	return err
+}
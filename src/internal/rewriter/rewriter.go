@@ -0,0 +1,127 @@
+package rewriter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type rewriter struct {
+	contracts.RewriterConfig
+	logger *logrus.Logger
+}
+
+func New(logger *logrus.Logger, config contracts.RewriterConfig) (contracts.Rewriter, error) {
+	if config.DryRun && config.Diff == nil {
+		return nil, errors.New("dry run requires somewhere to write the diff")
+	}
+	return &rewriter{
+		RewriterConfig: config,
+		logger:         logger,
+	}, nil
+}
+
+// splitLines cuts content after each line feed, so joining the lines gives back the exact same content
+func splitLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func splitEnding(line string) (string, string) {
+	for _, ending := range []string{"\r\n", "\n"} {
+		if strings.HasSuffix(line, ending) {
+			return strings.TrimSuffix(line, ending), ending
+		}
+	}
+	return line, ""
+}
+
+func (me *rewriter) Rewrite(filename string, lines map[uint]contracts.LineRewriter) ([]uint, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	before := splitLines(string(content))
+	after := append([]string{}, before...)
+	changed := []int{}
+	for _, entry := range utils.SortedMap(lines) {
+		if entry.Key < 1 || int(entry.Key) > len(before) {
+			return nil, fmt.Errorf("%s:%d: line out of range", filename, entry.Key)
+		}
+		idx := int(entry.Key) - 1
+		line, ending := splitEnding(before[idx])
+		rewritten, err := entry.Value(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filename, entry.Key, err)
+		}
+		if rewritten == line {
+			continue
+		}
+		after[idx] = rewritten + ending
+		changed = append(changed, idx)
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	numbers := utils.MapSlice(changed, func(idx int) uint {
+		return uint(idx + 1)
+	})
+
+	if me.DryRun {
+		return numbers, writeDiff(me.Diff, filename, before, after, changed)
+	}
+
+	me.logger.Infof("rewriting %d line(s) of %s", len(changed), filename)
+	return numbers, replaceFile(filename, strings.Join(after, ""))
+}
+
+// replaceFile writes the content next to the file before renaming it over the file, so that an interruption never
+// leaves it truncated, symlinks are followed to replace their target and the permissions of the file are kept
+func replaceFile(filename string, content string) (err error) {
+	target, err := filepath.EvalSymlinks(filename)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".gofixit-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+		}
+	}()
+	_, err = file.WriteString(content)
+	if err != nil {
+		return err
+	}
+	err = file.Chmod(info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	err = file.Sync()
+	if err != nil {
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), target)
+}
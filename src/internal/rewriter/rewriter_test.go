@@ -0,0 +1,202 @@
+package rewriter
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func upper(line string) (string, error) {
+	return strings.ToUpper(line), nil
+}
+
+func Test_Rewrite(t *testing.T) {
+	numbered := func(count int, ending string) string {
+		builder := &strings.Builder{}
+		for i := 1; i <= count; i++ {
+			fmt.Fprintf(builder, "line %d%s", i, ending)
+		}
+		return builder.String()
+	}
+
+	tests := []struct {
+		name        string
+		content     string
+		lines       map[uint]contracts.LineRewriter
+		dryRun      bool
+		wantChanged []uint
+		wantContent string
+		wantDiff    string
+		wantErr     bool
+	}{
+		{
+			name:        "works",
+			content:     "a\nb\nc\n",
+			lines:       map[uint]contracts.LineRewriter{2: upper},
+			wantChanged: []uint{2},
+			wantContent: "a\nB\nc\n",
+		},
+		{
+			name:        "works, keeps CRLF and missing final newline",
+			content:     "a\r\nb\r\nc",
+			lines:       map[uint]contracts.LineRewriter{1: upper, 3: upper},
+			wantChanged: []uint{1, 3},
+			wantContent: "A\r\nb\r\nC",
+		},
+		{
+			name:        "works, no change",
+			content:     "A\nb\n",
+			lines:       map[uint]contracts.LineRewriter{1: upper},
+			wantContent: "A\nb\n",
+		},
+		{
+			name:        "works, only counts changed lines",
+			content:     "A\nb\nC\n",
+			lines:       map[uint]contracts.LineRewriter{1: upper, 2: upper, 3: upper},
+			wantChanged: []uint{2},
+			wantContent: "A\nB\nC\n",
+		},
+		{
+			name:        "works, dry run",
+			content:     numbered(12, "\n"),
+			lines:       map[uint]contracts.LineRewriter{2: upper, 3: upper, 11: upper},
+			dryRun:      true,
+			wantChanged: []uint{2, 3, 11},
+			wantContent: numbered(12, "\n"),
+			wantDiff: `--- a/FILE
++++ b/FILE
+@@ -1,6 +1,6 @@
+ line 1
+-line 2
+-line 3
++LINE 2
++LINE 3
+ line 4
+ line 5
+ line 6
+@@ -8,5 +8,5 @@
+ line 8
+ line 9
+ line 10
+-line 11
++LINE 11
+ line 12
+`,
+		},
+		{
+			name:        "works, dry run without final newline",
+			content:     "a\nb",
+			lines:       map[uint]contracts.LineRewriter{2: upper},
+			dryRun:      true,
+			wantChanged: []uint{2},
+			wantContent: "a\nb",
+			wantDiff: `--- a/FILE
++++ b/FILE
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++B
+\ No newline at end of file
+`,
+		},
+		{
+			name:        "fails, line out of range",
+			content:     "a\n",
+			lines:       map[uint]contracts.LineRewriter{2: upper},
+			wantContent: "a\n",
+			wantErr:     true,
+		},
+		{
+			name:    "fails, rewriter fails",
+			content: "a\n",
+			lines: map[uint]contracts.LineRewriter{1: func(line string) (string, error) {
+				return "", fmt.Errorf("nope")
+			}},
+			wantContent: "a\n",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "file.txt")
+			err := os.WriteFile(file, []byte(tt.content), 0o600)
+			if err != nil {
+				t.Fatalf("failed to create file: %v", err)
+			}
+
+			diff := &bytes.Buffer{}
+			me, err := New(logrus.New(), contracts.RewriterConfig{
+				DryRun: tt.dryRun,
+				Diff:   diff,
+			})
+			if err != nil {
+				t.Fatalf("failed to create rewriter: %v", err)
+			}
+
+			changed, err := me.Rewrite(file, tt.lines)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantChanged, changed)
+			assert.Equal(t, strings.ReplaceAll(tt.wantDiff, "FILE", file), diff.String())
+
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("failed to read file: %v", err)
+			}
+			assert.Equal(t, tt.wantContent, string(content))
+			info, err := os.Stat(file)
+			if err != nil {
+				t.Fatalf("failed to stat file: %v", err)
+			}
+			assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		})
+	}
+}
+
+func Test_Rewrite_ReplacesFile(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "main.go")
+	link := filepath.Join(dir, "link.go")
+	if !assert.NoError(t, os.WriteFile(target, []byte("a\nb\n"), 0o600)) {
+		return
+	}
+	if !assert.NoError(t, os.Symlink(target, link)) {
+		return
+	}
+
+	subject, err := New(logrus.New(), contracts.RewriterConfig{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	changed, err := subject.Rewrite(link, map[uint]contracts.LineRewriter{1: upper})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []uint{1}, changed)
+
+	content, err := os.ReadFile(target)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "A\nb\n", string(content))
+	}
+	info, err := os.Lstat(link)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.ModeSymlink, info.Mode().Type(), "the link is kept")
+	}
+	info, err = os.Stat(target)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "the permissions are kept")
+	}
+	entries, err := os.ReadDir(dir)
+	if assert.NoError(t, err) {
+		assert.Len(t, entries, 2, "no temporary file is left behind")
+	}
+}
@@ -0,0 +1,153 @@
+package stats
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type collector struct {
+	contracts.StatsConfig
+	logger *logrus.Logger
+	cwd    string
+}
+
+func New(logger *logrus.Logger, config contracts.StatsConfig) (contracts.StatsCollector, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if config.Root == "" {
+		config.Root = cwd
+	}
+	config.Root, err = filepath.Abs(config.Root)
+	if err != nil {
+		return nil, err
+	}
+	return &collector{
+		StatsConfig: config,
+		logger:      logger,
+		cwd:         cwd,
+	}, nil
+}
+
+// accumulator gathers what is needed to compute the metrics of a group of comments
+type accumulator struct {
+	contracts.Metrics
+	overdueAges []time.Duration
+}
+
+func (me *accumulator) add(now time.Time, implicitExpiry time.Duration, result contracts.Result) {
+	comment := result.Comment
+	me.Total += 1
+	switch {
+	case comment.InvalidExpiry != "":
+		me.Invalid += 1
+	case comment.Expiry == nil:
+		me.Undated += 1
+	default:
+		me.Dated += 1
+	}
+
+	expiry := comment.Expiry
+	if expiry == nil && comment.Blame != nil && implicitExpiry > 0 {
+		expiry = utils.Pointerize(comment.Blame.Date.Add(implicitExpiry))
+	}
+	if result.Status == contracts.StatusOverdue {
+		me.Overdue += 1
+		if expiry != nil {
+			me.overdueAges = append(me.overdueAges, now.Sub(*expiry))
+		}
+	} else if comment.Expiry != nil && (me.NextExpiry == nil || comment.Expiry.Before(*me.NextExpiry)) {
+		me.NextExpiry = comment.Expiry
+	}
+}
+
+func (me *accumulator) metrics() contracts.Metrics {
+	metrics := me.Metrics
+	metrics.MedianOverdueAge = median(me.overdueAges)
+	return metrics
+}
+
+func median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// topDirectory returns the first directory of a path (relative to cwd unless absolute) inside root, or "." for files
+// at the root, paths outside of root keep their leading "../" (e.g. "../other")
+func topDirectory(root, cwd, name string) string {
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(cwd, name)
+	}
+	if relative, err := filepath.Rel(root, name); err == nil {
+		name = relative
+	}
+	parts := strings.Split(path.Clean(filepath.ToSlash(name)), "/")
+	parents := 0
+	for parents < len(parts) && parts[parents] == ".." {
+		parents += 1
+	}
+	if parents == len(parts)-1 {
+		if parents == 0 {
+			return "."
+		}
+		return strings.Join(parts[:parents], "/")
+	}
+	return strings.Join(parts[:parents+1], "/")
+}
+
+func (me *collector) add(groups map[string]*accumulator, key string, result contracts.Result) {
+	group, found := groups[key]
+	if !found {
+		group = &accumulator{}
+		groups[key] = group
+	}
+	group.add(me.Now, me.ImplicitExpiry, result)
+}
+
+func finalize(groups map[string]*accumulator) map[string]contracts.Metrics {
+	metrics := make(map[string]contracts.Metrics, len(groups))
+	for key, group := range groups {
+		metrics[key] = group.metrics()
+	}
+	return metrics
+}
+
+func (me *collector) Collect(results []contracts.Result) contracts.Stats {
+	total := &accumulator{}
+	byDirectory := map[string]*accumulator{}
+	byExtension := map[string]*accumulator{}
+	byPrefix := map[string]*accumulator{}
+	for _, result := range results {
+		total.add(me.Now, me.ImplicitExpiry, result)
+		me.add(byDirectory, topDirectory(me.Root, me.cwd, result.Path), result)
+		me.add(byExtension, path.Ext(filepath.ToSlash(result.Path)), result)
+		me.add(byPrefix, result.Comment.Prefix, result)
+	}
+	me.logger.Debugf("collected stats for %d comments", total.Total)
+
+	return contracts.Stats{
+		Metrics:     total.metrics(),
+		ByDirectory: finalize(byDirectory),
+		ByExtension: finalize(byExtension),
+		ByPrefix:    finalize(byPrefix),
+	}
+}
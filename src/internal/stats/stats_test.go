@@ -0,0 +1,226 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Collect(t *testing.T) {
+	now := utils.Must(time.Parse("2006-01-02", "2022-06-19"))
+	date := func(value string) *time.Time {
+		return utils.Pointerize(utils.Must(time.Parse("2006-01-02", value)))
+	}
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name    string
+		results []contracts.Result
+		want    contracts.Stats
+	}{
+		{
+			name: "works without results",
+			want: contracts.Stats{
+				ByDirectory: map[string]contracts.Metrics{},
+				ByExtension: map[string]contracts.Metrics{},
+				ByPrefix:    map[string]contracts.Metrics{},
+			},
+		},
+		{
+			name: "works",
+			results: []contracts.Result{
+				{Path: "src/main.c", Status: contracts.StatusOverdue, Comment: contracts.ParsedComment{Prefix: "TODO", Expiry: date("2022-06-15")}},
+				{Path: "src/main.c", Status: contracts.StatusUndated, Comment: contracts.ParsedComment{Prefix: "FIXME"}},
+				{Path: "src/lib/util.go", Status: contracts.StatusOverdue, Comment: contracts.ParsedComment{Prefix: "TODO", Expiry: date("2022-06-09")}},
+				{Path: "src/lib/util.go", Status: contracts.StatusOK, Comment: contracts.ParsedComment{Prefix: "TODO", Expiry: date("2022-06-25")}},
+				{Path: "README", Status: contracts.StatusOK, Comment: contracts.ParsedComment{Prefix: "TODO", Expiry: date("2022-07-01")}},
+				{Path: "README", Status: contracts.StatusInvalid, Comment: contracts.ParsedComment{Prefix: "TODO", InvalidExpiry: "2022-13-01"}},
+			},
+			want: contracts.Stats{
+				Metrics: contracts.Metrics{
+					Total:            6,
+					Dated:            4,
+					Undated:          1,
+					Invalid:          1,
+					Overdue:          2,
+					MedianOverdueAge: 7 * day,
+					NextExpiry:       date("2022-06-25"),
+				},
+				ByDirectory: map[string]contracts.Metrics{
+					"src": {
+						Total:            4,
+						Dated:            3,
+						Undated:          1,
+						Overdue:          2,
+						MedianOverdueAge: 7 * day,
+						NextExpiry:       date("2022-06-25"),
+					},
+					".": {
+						Total:      2,
+						Dated:      1,
+						Invalid:    1,
+						NextExpiry: date("2022-07-01"),
+					},
+				},
+				ByExtension: map[string]contracts.Metrics{
+					".c": {
+						Total:            2,
+						Dated:            1,
+						Undated:          1,
+						Overdue:          1,
+						MedianOverdueAge: 4 * day,
+					},
+					".go": {
+						Total:            2,
+						Dated:            2,
+						Overdue:          1,
+						MedianOverdueAge: 10 * day,
+						NextExpiry:       date("2022-06-25"),
+					},
+					"": {
+						Total:      2,
+						Dated:      1,
+						Invalid:    1,
+						NextExpiry: date("2022-07-01"),
+					},
+				},
+				ByPrefix: map[string]contracts.Metrics{
+					"TODO": {
+						Total:            5,
+						Dated:            4,
+						Invalid:          1,
+						Overdue:          2,
+						MedianOverdueAge: 7 * day,
+						NextExpiry:       date("2022-06-25"),
+					},
+					"FIXME": {
+						Total:   1,
+						Undated: 1,
+					},
+				},
+			},
+		},
+		{
+			name: "works with implicit expiry",
+			results: []contracts.Result{
+				{Path: "main.go", Status: contracts.StatusOverdue, Comment: contracts.ParsedComment{Prefix: "TODO", Blame: &contracts.Blame{Date: *date("2022-03-11")}}},
+				{Path: "main.go", Status: contracts.StatusUndated, Comment: contracts.ParsedComment{Prefix: "TODO", Blame: &contracts.Blame{Date: *date("2022-06-01")}}},
+			},
+			want: contracts.Stats{
+				Metrics: contracts.Metrics{
+					Total:            2,
+					Undated:          2,
+					Overdue:          1,
+					MedianOverdueAge: 10 * day,
+				},
+				ByDirectory: map[string]contracts.Metrics{
+					".": {Total: 2, Undated: 2, Overdue: 1, MedianOverdueAge: 10 * day},
+				},
+				ByExtension: map[string]contracts.Metrics{
+					".go": {Total: 2, Undated: 2, Overdue: 1, MedianOverdueAge: 10 * day},
+				},
+				ByPrefix: map[string]contracts.Metrics{
+					"TODO": {Total: 2, Undated: 2, Overdue: 1, MedianOverdueAge: 10 * day},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			me, err := New(logrus.New(), contracts.StatsConfig{
+				Now:            now,
+				ImplicitExpiry: 90 * day,
+			})
+			if err != nil {
+				t.Fatalf("failed to create collector: %v", err)
+			}
+
+			assert.Equal(t, tt.want, me.Collect(tt.results))
+		})
+	}
+}
+
+func Test_topDirectory(t *testing.T) {
+	tests := []struct {
+		name string
+		root string
+		path string
+		want string
+	}{
+		{
+			name: "works with a file at the root",
+			root: "/repo",
+			path: "README",
+			want: ".",
+		},
+		{
+			name: "works with a relative path",
+			root: "/repo",
+			path: "src/lib/util.go",
+			want: "src",
+		},
+		{
+			name: "works with a dot prefix",
+			root: "/repo",
+			path: "./src/main.c",
+			want: "src",
+		},
+		{
+			name: "works with an absolute path inside root",
+			root: "/repo",
+			path: "/repo/src/lib/util.go",
+			want: "src",
+		},
+		{
+			name: "works with an absolute file at the root",
+			root: "/repo",
+			path: "/repo/README",
+			want: ".",
+		},
+		{
+			name: "works with an absolute path outside root",
+			root: "/repo",
+			path: "/other/x/y.go",
+			want: "../other",
+		},
+		{
+			name: "works with a parent path",
+			root: "/repo",
+			path: "../x/y.go",
+			want: "../x",
+		},
+		{
+			name: "works with a parent path inside root",
+			root: "/",
+			path: "../x/y.go",
+			want: "x",
+		},
+		{
+			name: "works with a file in a parent directory",
+			root: "/repo",
+			path: "../y.go",
+			want: "..",
+		},
+		{
+			name: "works with an absolute root scanned from elsewhere",
+			root: "/data/project",
+			path: "/data/project/lib/util.go",
+			want: "lib",
+		},
+		{
+			name: "works with archives",
+			root: "/repo",
+			path: "bundle.tar.gz!/src/main.c",
+			want: "bundle.tar.gz!",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, topDirectory(tt.root, "/repo", tt.path))
+		})
+	}
+}
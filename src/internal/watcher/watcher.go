@@ -0,0 +1,193 @@
+package watcher
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/utils"
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+type watcherImpl struct {
+	contracts.WatcherConfig
+	logger          *logrus.Logger
+	watcher         *fsnotify.Watcher
+	excludePatterns []regexp.Regexp
+	// dirs are watched for every file they contain, files are watched through their (untracked) parent directory
+	dirs    map[string]bool
+	files   map[string]bool
+	changes chan []string
+	errors  chan error
+	done    chan struct{}
+}
+
+func New(logger *logrus.Logger, config contracts.WatcherConfig) (contracts.Watcher, error) {
+	excludePatterns := make([]regexp.Regexp, 0, len(config.FilesExcludePatterns))
+	for _, excludePattern := range config.FilesExcludePatterns {
+		re, err := regexp.Compile(excludePattern)
+		if err != nil {
+			return nil, err
+		}
+		excludePatterns = append(excludePatterns, *re)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	me := &watcherImpl{
+		WatcherConfig:   config,
+		logger:          logger,
+		watcher:         watcher,
+		excludePatterns: excludePatterns,
+		dirs:            map[string]bool{},
+		files:           map[string]bool{},
+		changes:         make(chan []string),
+		errors:          make(chan error),
+		done:            make(chan struct{}),
+	}
+	for _, path := range config.Paths {
+		err = me.add(filepath.Clean(path))
+		if err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+	go me.loop()
+	return me, nil
+}
+
+func (me *watcherImpl) add(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		me.files[path] = true
+		return me.watcher.Add(filepath.Dir(path))
+	}
+	if !me.Recursive {
+		return me.addDir(path)
+	}
+	return filepath.WalkDir(path, func(current string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if current != path && (entry.Name() == ".git" || me.excluded(current)) {
+			return filepath.SkipDir
+		}
+		return me.addDir(current)
+	})
+}
+
+// excluded matches the path, both as given and absolute, against the exclude patterns like the files processor does
+func (me *watcherImpl) excluded(path string) bool {
+	needles := []string{path}
+	absPath, err := filepath.Abs(path)
+	if err == nil {
+		needles = append(needles, absPath)
+	}
+	for _, needle := range needles {
+		for _, excludePattern := range me.excludePatterns {
+			if excludePattern.MatchString(needle) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (me *watcherImpl) addDir(path string) error {
+	me.logger.Debugf("watching %s", path)
+	me.dirs[path] = true
+	return me.watcher.Add(path)
+}
+
+// relevant skips permission changes, excluded files and files which were not asked for
+func (me *watcherImpl) relevant(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod || filepath.Base(event.Name) == ".git" {
+		return false
+	}
+	if me.files[event.Name] {
+		return true
+	}
+	return me.dirs[filepath.Dir(event.Name)] && !me.excluded(event.Name)
+}
+
+func (me *watcherImpl) loop() {
+	pending := map[string]bool{}
+	var fire <-chan time.Time
+	for {
+		select {
+		case <-me.done:
+			return
+		case event, ok := <-me.watcher.Events:
+			if !ok {
+				return
+			}
+			event.Name = filepath.Clean(event.Name)
+			if !me.relevant(event) {
+				continue
+			}
+			me.logger.Debugf("change detected: %s", event)
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if !me.Recursive {
+						continue
+					}
+					err = me.add(event.Name)
+					if err != nil {
+						me.send(err)
+					}
+				}
+			}
+			pending[event.Name] = true
+			fire = time.After(me.Debounce)
+		case err, ok := <-me.watcher.Errors:
+			if !ok {
+				return
+			}
+			me.send(err)
+		case <-fire:
+			batch := make([]string, 0, len(pending))
+			for _, entry := range utils.SortedMap(pending) {
+				batch = append(batch, entry.Key)
+			}
+			pending = map[string]bool{}
+			fire = nil
+			select {
+			case me.changes <- batch:
+			case <-me.done:
+				return
+			}
+		}
+	}
+}
+
+func (me *watcherImpl) send(err error) {
+	select {
+	case me.errors <- err:
+	case <-me.done:
+	}
+}
+
+func (me *watcherImpl) Changes() <-chan []string {
+	return me.changes
+}
+
+func (me *watcherImpl) Errors() <-chan error {
+	return me.errors
+}
+
+func (me *watcherImpl) Close() error {
+	close(me.done)
+	return me.watcher.Close()
+}
@@ -0,0 +1,116 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func nextBatch(t *testing.T, watcher contracts.Watcher) []string {
+	select {
+	case batch := <-watcher.Changes():
+		return batch
+	case err := <-watcher.Errors():
+		t.Fatalf("unexpected error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for changes")
+	}
+	return nil
+}
+
+func write(t *testing.T, path string) {
+	err := os.WriteFile(path, []byte("// TODO: something\n"), 0o644)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+}
+
+func Test_Watcher_Directories(t *testing.T) {
+	root := t.TempDir()
+	write(t, filepath.Join(root, "a.go"))
+
+	watcher, err := New(logrus.New(), contracts.WatcherConfig{
+		Paths:     []string{root},
+		Recursive: true,
+		Debounce:  50 * time.Millisecond,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer watcher.Close()
+
+	write(t, filepath.Join(root, "a.go"))
+	write(t, filepath.Join(root, "b.go"))
+	assert.Equal(t, []string{filepath.Join(root, "a.go"), filepath.Join(root, "b.go")}, nextBatch(t, watcher))
+
+	sub := filepath.Join(root, "sub")
+	assert.NoError(t, os.Mkdir(sub, 0o755))
+	assert.Equal(t, []string{sub}, nextBatch(t, watcher))
+
+	write(t, filepath.Join(sub, "c.go"))
+	assert.Equal(t, []string{filepath.Join(sub, "c.go")}, nextBatch(t, watcher))
+
+	assert.NoError(t, os.Remove(filepath.Join(root, "a.go")))
+	assert.Equal(t, []string{filepath.Join(root, "a.go")}, nextBatch(t, watcher))
+}
+
+func Test_Watcher_Files(t *testing.T) {
+	root := t.TempDir()
+	watched := filepath.Join(root, "watched.go")
+	write(t, watched)
+
+	watcher, err := New(logrus.New(), contracts.WatcherConfig{
+		Paths:    []string{watched},
+		Debounce: 50 * time.Millisecond,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer watcher.Close()
+
+	write(t, filepath.Join(root, "other.go"))
+	write(t, watched)
+	assert.Equal(t, []string{watched}, nextBatch(t, watcher))
+}
+
+func Test_Watcher_Excluded(t *testing.T) {
+	root := t.TempDir()
+	vendor := filepath.Join(root, "vendor")
+	assert.NoError(t, os.Mkdir(vendor, 0o755))
+
+	watcher, err := New(logrus.New(), contracts.WatcherConfig{
+		Paths:                []string{root},
+		Recursive:            true,
+		FilesExcludePatterns: []string{"/vendor$", `\.gen\.go$`},
+		Debounce:             50 * time.Millisecond,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer watcher.Close()
+
+	write(t, filepath.Join(vendor, "lib.go"))
+	write(t, filepath.Join(root, "a.gen.go"))
+	write(t, filepath.Join(root, "a.go"))
+	assert.Equal(t, []string{filepath.Join(root, "a.go")}, nextBatch(t, watcher))
+}
+
+func Test_Watcher_InvalidPattern(t *testing.T) {
+	_, err := New(logrus.New(), contracts.WatcherConfig{
+		Paths:                []string{t.TempDir()},
+		FilesExcludePatterns: []string{"("},
+	})
+	assert.Error(t, err)
+}
+
+func Test_Watcher_Missing(t *testing.T) {
+	_, err := New(logrus.New(), contracts.WatcherConfig{
+		Paths: []string{filepath.Join(t.TempDir(), "missing")},
+	})
+	assert.Error(t, err)
+}
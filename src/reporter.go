@@ -0,0 +1,11 @@
+package gofixit
+
+import (
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/internal/reporter"
+	"github.com/sirupsen/logrus"
+)
+
+func NewReporter(logger *logrus.Logger, config contracts.ReporterConfig) (contracts.Reporter, error) {
+	return reporter.New(logger, config)
+}
@@ -0,0 +1,11 @@
+package gofixit
+
+import (
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/internal/rewriter"
+	"github.com/sirupsen/logrus"
+)
+
+func NewRewriter(logger *logrus.Logger, config contracts.RewriterConfig) (contracts.Rewriter, error) {
+	return rewriter.New(logger, config)
+}
@@ -0,0 +1,11 @@
+package gofixit
+
+import (
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/internal/stats"
+	"github.com/sirupsen/logrus"
+)
+
+func NewStatsCollector(logger *logrus.Logger, config contracts.StatsConfig) (contracts.StatsCollector, error) {
+	return stats.New(logger, config)
+}
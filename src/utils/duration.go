@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+var longUnitsRE = regexp.MustCompile(`^([0-9]+)([wd])`)
+
+// ParseDuration extends time.ParseDuration with days (d) and weeks (w), e.g. "+90d", "2w" or "1d12h"
+func ParseDuration(value string) (time.Duration, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(value), "+")
+	sign := time.Duration(1)
+	if strings.HasPrefix(rest, "-") {
+		sign = -1
+		rest = rest[1:]
+	}
+	if rest == "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	total := time.Duration(0)
+	for {
+		matches := longUnitsRE.FindStringSubmatch(rest)
+		if matches == nil {
+			break
+		}
+		count, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+		}
+		unit := day
+		if matches[2] == "w" {
+			unit = 7 * day
+		}
+		total += time.Duration(count) * unit
+		rest = rest[len(matches[0]):]
+	}
+
+	if rest != "" {
+		parsed, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+		}
+		total += parsed
+	}
+	return sign * total, nil
+}
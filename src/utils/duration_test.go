@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		result  time.Duration
+		wantErr bool
+	}{
+		{
+			name:   "days",
+			value:  "30d",
+			result: 30 * 24 * time.Hour,
+		},
+		{
+			name:   "positive days",
+			value:  "+90d",
+			result: 90 * 24 * time.Hour,
+		},
+		{
+			name:   "weeks",
+			value:  "2w",
+			result: 14 * 24 * time.Hour,
+		},
+		{
+			name:   "mixed",
+			value:  "1w2d12h30m",
+			result: 9*24*time.Hour + 12*time.Hour + 30*time.Minute,
+		},
+		{
+			name:   "negative",
+			value:  "-1d",
+			result: -24 * time.Hour,
+		},
+		{
+			name:   "standard",
+			value:  "36h",
+			result: 36 * time.Hour,
+		},
+		{
+			name:    "empty",
+			value:   "",
+			wantErr: true,
+		},
+		{
+			name:    "invalid",
+			value:   "3 days",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDuration(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.result, result)
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package gofixit
+
+import (
+	"github.com/LouisBrunner/gofixit/src/contracts"
+	"github.com/LouisBrunner/gofixit/src/internal/watcher"
+	"github.com/sirupsen/logrus"
+)
+
+func NewWatcher(logger *logrus.Logger, config contracts.WatcherConfig) (contracts.Watcher, error) {
+	return watcher.New(logger, config)
+}